@@ -0,0 +1,103 @@
+package simpleforce
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+)
+
+// AggregateResult is a row returned by an aggregate SOQL query, such as
+// "SELECT Department, COUNT(Id) total FROM User GROUP BY ROLLUP(Department)". Its values are keyed by the grouped
+// field names and by the aliases of the aggregate functions; aggregates without an alias are named expr0, expr1, etc.
+// in the order of the query. Keys are matched case-insensitively.
+type AggregateResult map[string]interface{}
+
+// QueryAggregate runs an aggregate SOQL query and returns all rows. Rows can also be decoded into structs with
+// QueryAs, using the aliases as field names.
+// Ref: https://developer.salesforce.com/docs/atlas.en-us.soql_sosl.meta/soql_sosl/sforce_api_calls_soql_select_groupby.htm
+func (client *Client) QueryAggregate(ctx context.Context, q string, opts ...CallOption) ([]AggregateResult, error) {
+	var results []AggregateResult
+	it := client.QueryIter(ctx, q, opts...)
+	for it.Next() {
+		results = append(results, AggregateResult(*it.Record()))
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// Count runs a COUNT() query, e.g. "SELECT COUNT() FROM User WHERE IsActive = true", and returns the number of
+// matching records.
+// Ref: https://developer.salesforce.com/docs/atlas.en-us.soql_sosl.meta/soql_sosl/sforce_api_calls_soql_select_count.htm
+func (client *Client) Count(ctx context.Context, q string, opts ...CallOption) (int, error) {
+	result, err := client.Query(ctx, q, opts...)
+	if err != nil {
+		return 0, err
+	}
+	return result.TotalSize, nil
+}
+
+// Value returns the value of alias, or nil if it is null or missing.
+func (r AggregateResult) Value(alias string) interface{} {
+	value, _ := lookupField(r, alias)
+	return value
+}
+
+// IsNull reports whether the value of alias is null or missing. Grouped fields are null in the subtotal rows of
+// ROLLUP and CUBE queries.
+func (r AggregateResult) IsNull(alias string) bool {
+	return r.Value(alias) == nil
+}
+
+// Float returns the numeric value of alias, as returned by COUNT, SUM, AVG, MIN and MAX. ok is false if the value is
+// null or not a number.
+func (r AggregateResult) Float(alias string) (value float64, ok bool) {
+	switch v := r.Value(alias).(type) {
+	case float64:
+		return v, true
+	case string:
+		f, err := strconv.ParseFloat(v, 64)
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
+
+// Int returns the numeric value of alias as an integer, e.g. for COUNT. ok is false if the value is null, not a
+// number, or has a fractional part.
+func (r AggregateResult) Int(alias string) (value int64, ok bool) {
+	f, ok := r.Float(alias)
+	if !ok || f != math.Trunc(f) {
+		return 0, false
+	}
+	return int64(f), true
+}
+
+// StringValue returns the value of alias as a string, e.g. for grouped text or picklist fields. Numbers and booleans
+// are formatted, and null values return "".
+func (r AggregateResult) StringValue(alias string) string {
+	switch v := r.Value(alias).(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// Time returns the date or datetime value of alias, e.g. for MIN(CreatedDate). ok is false if the value is null or
+// not a date.
+func (r AggregateResult) Time(alias string) (value time.Time, ok bool) {
+	s, isString := r.Value(alias).(string)
+	if !isString {
+		return time.Time{}, false
+	}
+	t, err := parseSalesforceTime(s)
+	return t, err == nil
+}
@@ -0,0 +1,70 @@
+package simpleforce
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestClient_QueryAggregate(t *testing.T) {
+	ctx := context.Background()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/services/data/v"+DefaultAPIVersion+"/query", func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("q") {
+		case "SELECT COUNT() FROM User":
+			_, _ = w.Write([]byte(`{"totalSize": 1234, "done": true, "records": []}`))
+		default:
+			_, _ = w.Write([]byte(`{"totalSize": 3, "done": true, "records": [
+				{"attributes": {"type": "AggregateResult"}, "Department": "Sales", "total": 12, "expr0": 1500.5, "oldest": "2020-01-02T03:04:05.000+0000"},
+				{"attributes": {"type": "AggregateResult"}, "Department": "IT", "total": 3, "expr0": 20, "oldest": "2021-06-07T08:09:10.000+0000"},
+				{"attributes": {"type": "AggregateResult"}, "Department": null, "total": 15, "expr0": 1520.5, "oldest": "2020-01-02T03:04:05.000+0000"}
+			]}`))
+		}
+	})
+	client := newLoggedInTestClient(t, mux)
+
+	results, err := client.QueryAggregate(ctx,
+		"SELECT Department, COUNT(Id) total, SUM(Budget__c), MIN(CreatedDate) oldest FROM User GROUP BY ROLLUP(Department)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("unexpected results %+v", results)
+	}
+
+	sales := results[0]
+	if total, ok := sales.Int("Total"); !ok || total != 12 || sales.StringValue("Department") != "Sales" {
+		t.Fatalf("unexpected row %+v", sales)
+	}
+	if sum, ok := sales.Float("expr0"); !ok || sum != 1500.5 || sales.StringValue("expr0") != "1500.5" {
+		t.Fatalf("unexpected sum in row %+v", sales)
+	}
+	if _, ok := sales.Int("expr0"); ok {
+		t.Fatal("expected a fractional sum not to be an integer")
+	}
+	if oldest, ok := sales.Time("oldest"); !ok || !oldest.Equal(time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)) {
+		t.Fatalf("unexpected date in row %+v", sales)
+	}
+
+	// The ROLLUP subtotal row has no department.
+	rollup := results[2]
+	if !rollup.IsNull("Department") || rollup.StringValue("Department") != "" {
+		t.Fatalf("unexpected subtotal row %+v", rollup)
+	}
+	if total, ok := rollup.Int("total"); !ok || total != 15 {
+		t.Fatalf("unexpected subtotal row %+v", rollup)
+	}
+	if _, ok := rollup.Float("missing"); ok {
+		t.Fatal("expected no value for a missing alias")
+	}
+
+	count, err := client.Count(ctx, "SELECT COUNT() FROM User")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 1234 {
+		t.Fatalf("unexpected count %d", count)
+	}
+}
@@ -0,0 +1,102 @@
+package simpleforce
+
+import (
+	"context"
+	"fmt"
+)
+
+// ChildRecords returns the records of the child relationship subquery relationship, e.g. "PermissionSetAssignments"
+// for "SELECT Id, (SELECT Id FROM PermissionSetAssignments) FROM User". Like top-level queries, subqueries return
+// their records in batches; the remaining batches are fetched and stored in the SObject, so that later calls and
+// Decode return all child records. nil is returned if the parent has no child records.
+func (obj *SObject) ChildRecords(ctx context.Context, relationship string) ([]SObject, error) {
+	key, ok := fieldKey(*obj, relationship)
+	if !ok || (*obj)[key] == nil {
+		return nil, nil
+	}
+	child, ok := (*obj)[key].(map[string]interface{})
+	if !ok || !isChildResult(child) {
+		return nil, fmt.Errorf("%s is not a child relationship", relationship)
+	}
+
+	var records []SObject
+	rawRecords, _ := child["records"].([]interface{})
+	for _, raw := range rawRecords {
+		record, ok := raw.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("invalid record in child relationship %s", relationship)
+		}
+		records = append(records, SObject(record))
+	}
+
+	done, _ := child["done"].(bool)
+	next, _ := child["nextRecordsUrl"].(string)
+	if !done && next != "" {
+		client := obj.client()
+		if client == nil {
+			return nil, ErrNoTypeIdClientOrId
+		}
+		for !done && next != "" {
+			result, err := client.Query(ctx, next)
+			if err != nil {
+				return nil, err
+			}
+			records = append(records, result.Records...)
+			done, next = result.Done, result.NextRecordsURL
+		}
+
+		// Store all records, so that they don't need to be fetched again.
+		all := make([]interface{}, len(records))
+		for i, record := range records {
+			all[i] = map[string]interface{}(copyFields(record))
+		}
+		(*obj)[key] = map[string]interface{}{
+			"totalSize": len(records),
+			"done":      true,
+			"records":   all,
+		}
+	}
+
+	// Reference to client is needed if the object will be further used to do online queries. It is set on copies of the
+	// records, so that it doesn't end up in the fields of the parent, e.g. when the parent is marshaled.
+	for idx := range records {
+		records[idx] = copyFields(records[idx])
+		records[idx].setClient(obj.client())
+	}
+	return records, nil
+}
+
+// copyFields returns a shallow copy of the fields of record, without its client.
+func copyFields(record SObject) SObject {
+	fields := make(SObject, len(record))
+	for key, value := range record {
+		if key != sobjectClientKey {
+			fields[key] = value
+		}
+	}
+	return fields
+}
+
+// FetchChildRecords fetches the remaining batches of all child relationship subqueries of the SObject, as done by
+// ChildRecords. Call it for each record of a query before decoding the records with Decode, to decode all child
+// records.
+func (obj *SObject) FetchChildRecords(ctx context.Context) error {
+	for key, value := range *obj {
+		child, ok := value.(map[string]interface{})
+		if !ok || !isChildResult(child) {
+			continue
+		}
+		_, err := obj.ChildRecords(ctx, key)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isChildResult returns if the value of a field is the result of a child relationship subquery.
+func isChildResult(value map[string]interface{}) bool {
+	_, hasRecords := value["records"]
+	_, hasDone := value["done"]
+	return hasRecords && hasDone
+}
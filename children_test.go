@@ -0,0 +1,153 @@
+package simpleforce
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// newTestChildRecordsServer returns a handler serving a query of two users, the first of which has 5
+// PermissionSetAssignments returned in 3 batches.
+func newTestChildRecordsServer() *http.ServeMux {
+	prefix := "/services/data/v" + DefaultAPIVersion + "/query"
+	mux := http.NewServeMux()
+	mux.HandleFunc(prefix, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"totalSize": 2, "done": true, "records": [
+			{
+				"attributes": {"type": "User"},
+				"Id": "005000000000001AAA",
+				"PermissionSetAssignments": {
+					"totalSize": 5,
+					"done": false,
+					"nextRecordsUrl": "` + prefix + `/01g000000000002AAA-2",
+					"records": [
+						{"attributes": {"type": "PermissionSetAssignment"}, "Id": "0Pa000000000000AAA"},
+						{"attributes": {"type": "PermissionSetAssignment"}, "Id": "0Pa000000000001AAA"}
+					]
+				}
+			},
+			{"attributes": {"type": "User"}, "Id": "005000000000002AAA", "PermissionSetAssignments": null}
+		]}`))
+	})
+	mux.HandleFunc(prefix+"/", func(w http.ResponseWriter, r *http.Request) {
+		switch strings.TrimPrefix(r.URL.Path, prefix+"/") {
+		case "01g000000000002AAA-2":
+			_, _ = w.Write([]byte(`{"totalSize": 5, "done": false, "nextRecordsUrl": "` + prefix + `/01g000000000002AAA-4", "records": [
+				{"attributes": {"type": "PermissionSetAssignment"}, "Id": "0Pa000000000002AAA"},
+				{"attributes": {"type": "PermissionSetAssignment"}, "Id": "0Pa000000000003AAA"}
+			]}`))
+		case "01g000000000002AAA-4":
+			_, _ = w.Write([]byte(`{"totalSize": 5, "done": true, "records": [
+				{"attributes": {"type": "PermissionSetAssignment"}, "Id": "0Pa000000000004AAA"}
+			]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	return mux
+}
+
+func TestSObject_ChildRecords(t *testing.T) {
+	ctx := context.Background()
+
+	client := newLoggedInTestClient(t, newTestChildRecordsServer())
+
+	result, err := client.Query(ctx, "SELECT Id, (SELECT Id FROM PermissionSetAssignments) FROM User")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	user := result.Records[0]
+	assignments, err := user.ChildRecords(ctx, "permissionsetassignments")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(assignments) != 5 || assignments[4].ID() != "0Pa000000000004AAA" || assignments[4].client() != client {
+		t.Fatalf("unexpected child records %+v", assignments)
+	}
+
+	// All child records are stored in the parent and can be decoded.
+	var decoded struct {
+		Assignments []struct {
+			ID string `sf:"Id"`
+		} `sf:"PermissionSetAssignments"`
+	}
+	if err := user.Decode(&decoded); err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded.Assignments) != 5 {
+		t.Fatalf("unexpected decoded child records %+v", decoded)
+	}
+
+	// The client is only set on the records returned, not on the records stored in the parent.
+	if _, err := user.ChildRecords(ctx, "PermissionSetAssignments"); err != nil {
+		t.Fatal(err)
+	}
+	stored := user.InterfaceField("PermissionSetAssignments").(map[string]interface{})["records"].([]interface{})
+	for _, record := range stored {
+		if _, ok := record.(map[string]interface{})[sobjectClientKey]; ok {
+			t.Fatalf("unexpected client in stored child record %v", record)
+		}
+	}
+
+	// Parents without child records.
+	if assignments, err := result.Records[1].ChildRecords(ctx, "PermissionSetAssignments"); err != nil || assignments != nil {
+		t.Fatalf("expected no child records, got %v, %v", assignments, err)
+	}
+	if err := result.Records[1].FetchChildRecords(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// Negative: not a child relationship.
+	if _, err := user.ChildRecords(ctx, "Id"); err == nil {
+		t.Fatal("expected error for a field which isn't a child relationship")
+	}
+}
+
+func TestSObject_FetchChildRecords(t *testing.T) {
+	ctx := context.Background()
+
+	server := newTestQueryServer(t, 5, 2)
+	client := newLoggedInTestClient(t, server)
+
+	account := client.SObject("Account").Set("Users", map[string]interface{}{
+		"totalSize":      5,
+		"done":           false,
+		"nextRecordsUrl": "/services/data/v" + DefaultAPIVersion + "/query/" + testQueryLocator + "-2",
+		"records":        []interface{}{},
+	})
+	if err := account.FetchChildRecords(ctx); err != nil {
+		t.Fatal(err)
+	}
+	users, err := account.ChildRecords(ctx, "Users")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The first batch of the subquery was empty here, so only the records from the offset 2 are returned.
+	if len(users) != 3 || users[0].StringField("Name") != "User 2" || server.requestCount() != 2 {
+		t.Fatalf("unexpected child records %+v after %d requests", users, server.requestCount())
+	}
+}
+
+func TestQueryAs_ChildRecords(t *testing.T) {
+	ctx := context.Background()
+
+	client := newLoggedInTestClient(t, newTestChildRecordsServer())
+
+	type user struct {
+		ID          string `sf:"Id"`
+		Assignments []struct {
+			ID string `sf:"Id"`
+		} `sf:"PermissionSetAssignments"`
+	}
+	users, err := QueryAs[user](ctx, client, "SELECT Id, (SELECT Id FROM PermissionSetAssignments) FROM User")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// All batches of the subquery are decoded, not just the first one.
+	if len(users) != 2 || len(users[0].Assignments) != 5 || users[0].Assignments[4].ID != "0Pa000000000004AAA" ||
+		users[1].Assignments != nil {
+		t.Fatalf("unexpected users %+v", users)
+	}
+}
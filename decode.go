@@ -0,0 +1,289 @@
+package simpleforce
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Layouts of the date, datetime and time values returned by salesforce.
+const (
+	salesforceDateTimeLayout = "2006-01-02T15:04:05.000-0700"
+	salesforceDateLayout     = "2006-01-02"
+	salesforceTimeLayout     = "15:04:05.000Z"
+)
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	sobjectType       = reflect.TypeOf(SObject{})
+	jsonUnmarshalType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+)
+
+// QueryAs runs the SOQL query q, following all batches, and decodes each record into a T as done by
+// QueryIterator.Decode, including all records of child relationship subqueries. T is usually a struct type, or a
+// pointer to one. opts customize the requests like for QueryIter.
+func QueryAs[T any](ctx context.Context, client *Client, q string, opts ...CallOption) ([]T, error) {
+	var results []T
+	it := client.QueryIter(ctx, q, opts...)
+	for it.Next() {
+		var result T
+		err := it.Decode(&result)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// Decode decodes the current record of the iterator into v as done by SObject.Decode, after fetching the remaining
+// batches of its child relationship subqueries with FetchChildRecords.
+func (it *QueryIterator) Decode(v interface{}) error {
+	if it.record == nil {
+		return errors.New("no current record")
+	}
+	err := it.record.FetchChildRecords(it.ctx)
+	if err != nil {
+		return err
+	}
+	return it.record.Decode(v)
+}
+
+// Decode copies the fields of the SObject into the struct pointed to by v. Each exported struct field is filled from
+// the SObject field named by its `sf` tag, falling back to its `json` tag and then to its name; the tag "-" skips the
+// field. Names are matched case-insensitively, as SOQL is, and may be a path into related objects, e.g.
+// `sf:"Account.Owner.Name"`. Related objects also decode into nested structs (or pointers to them), and the records
+// of child relationship subqueries decode into slices; only the batches fetched so far are decoded, so call
+// FetchChildRecords first, or use QueryIterator.Decode or QueryAs, which do. Date and datetime fields decode into
+// time.Time, and multi-select picklists into []string. Fields which are missing or null are left untouched.
+func (obj *SObject) Decode(v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return errors.New("decode target must be a non-nil pointer")
+	}
+	return decodeValue(map[string]interface{}(*obj), rv.Elem(), "")
+}
+
+// decodeValue decodes the raw value of a field into dst. path is the name of the field, used in error messages.
+func decodeValue(raw interface{}, dst reflect.Value, path string) error {
+	if raw == nil {
+		return nil
+	}
+
+	if dst.Kind() == reflect.Pointer {
+		if dst.IsNil() {
+			dst.Set(reflect.New(dst.Type().Elem()))
+		}
+		return decodeValue(raw, dst.Elem(), path)
+	}
+
+	switch {
+	case dst.Type() == timeType:
+		s, ok := raw.(string)
+		if !ok {
+			return fmt.Errorf("decoding %s: expected a date, got %T", path, raw)
+		}
+		t, err := parseSalesforceTime(s)
+		if err != nil {
+			return fmt.Errorf("decoding %s: %w", path, err)
+		}
+		dst.Set(reflect.ValueOf(t))
+		return nil
+	case dst.Type() == sobjectType:
+		fields, ok := raw.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("decoding %s: expected an object, got %T", path, raw)
+		}
+		dst.Set(reflect.ValueOf(SObject(fields)))
+		return nil
+	case reflect.PointerTo(dst.Type()).Implements(jsonUnmarshalType):
+		return decodeJSON(raw, dst, path)
+	}
+
+	switch dst.Kind() {
+	case reflect.Interface:
+		if !reflect.TypeOf(raw).AssignableTo(dst.Type()) {
+			return fmt.Errorf("decoding %s: %T does not implement %s", path, raw, dst.Type())
+		}
+		dst.Set(reflect.ValueOf(raw))
+		return nil
+	case reflect.Struct:
+		fields, ok := raw.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("decoding %s: expected an object, got %T", path, raw)
+		}
+		return decodeStruct(fields, dst, path)
+	case reflect.Slice:
+		return decodeSlice(raw, dst, path)
+	default:
+		return decodeJSON(raw, dst, path)
+	}
+}
+
+// decodeStruct decodes the fields of an object into the struct dst.
+func decodeStruct(fields map[string]interface{}, dst reflect.Value, path string) error {
+	dstType := dst.Type()
+	for i := 0; i < dstType.NumField(); i++ {
+		field := dstType.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		// Embedded structs without a name of their own are decoded from the same object.
+		if field.Anonymous && field.Tag.Get("sf") == "" && field.Tag.Get("json") == "" {
+			embedded := dst.Field(i)
+			if embedded.Kind() == reflect.Pointer {
+				if embedded.IsNil() {
+					embedded.Set(reflect.New(field.Type.Elem()))
+				}
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				err := decodeStruct(fields, embedded, path)
+				if err != nil {
+					return err
+				}
+				continue
+			}
+		}
+
+		name := fieldName(field)
+		if name == "" {
+			continue
+		}
+
+		raw, ok := lookupField(fields, name)
+		if !ok {
+			continue
+		}
+
+		fieldPath := name
+		if path != "" {
+			fieldPath = path + "." + name
+		}
+		err := decodeValue(raw, dst.Field(i), fieldPath)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// decodeSlice decodes the records of a child relationship subquery, a JSON array or a multi-select picklist into dst.
+func decodeSlice(raw interface{}, dst reflect.Value, path string) error {
+	var items []interface{}
+	switch raw := raw.(type) {
+	case []interface{}:
+		items = raw
+	case map[string]interface{}:
+		// A child relationship subquery is returned as a nested query result.
+		records, ok := raw["records"].([]interface{})
+		if !ok {
+			return fmt.Errorf("decoding %s: expected a query result", path)
+		}
+		items = records
+	case string:
+		if dst.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("decoding %s: expected a list, got a string", path)
+		}
+		if raw == "" {
+			dst.Set(reflect.MakeSlice(dst.Type(), 0, 0))
+			return nil
+		}
+		for _, value := range strings.Split(raw, ";") {
+			items = append(items, value)
+		}
+	default:
+		return decodeJSON(raw, dst, path)
+	}
+
+	slice := reflect.MakeSlice(dst.Type(), len(items), len(items))
+	for i, item := range items {
+		err := decodeValue(item, slice.Index(i), fmt.Sprintf("%s[%d]", path, i))
+		if err != nil {
+			return err
+		}
+	}
+	dst.Set(slice)
+	return nil
+}
+
+// decodeJSON decodes raw into dst by round-tripping it through JSON, which covers scalars and json.Unmarshaler.
+func decodeJSON(raw interface{}, dst reflect.Value, path string) error {
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return fmt.Errorf("decoding %s: %w", path, err)
+	}
+	err = json.Unmarshal(data, dst.Addr().Interface())
+	if err != nil {
+		return fmt.Errorf("decoding %s: %w", path, err)
+	}
+	return nil
+}
+
+// fieldName returns the SObject field name of a struct field, or "" if it is skipped.
+func fieldName(field reflect.StructField) string {
+	for _, key := range []string{"sf", "json"} {
+		tag, ok := field.Tag.Lookup(key)
+		if !ok {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if name == "-" {
+			return ""
+		}
+		if name != "" {
+			return name
+		}
+	}
+	return field.Name
+}
+
+// lookupField returns the value of the field at the dotted path name, matching each name case-insensitively.
+func lookupField(fields map[string]interface{}, name string) (interface{}, bool) {
+	head, rest, nested := strings.Cut(name, ".")
+
+	key, ok := fieldKey(fields, head)
+	value := fields[key]
+	if !ok || !nested {
+		return value, ok
+	}
+
+	// A null relationship leaves the whole path empty.
+	related, isMap := value.(map[string]interface{})
+	if !isMap {
+		return nil, false
+	}
+	return lookupField(related, rest)
+}
+
+// fieldKey returns the key of the field name, matching it case-insensitively if there is no exact match.
+func fieldKey(fields map[string]interface{}, name string) (string, bool) {
+	if _, ok := fields[name]; ok {
+		return name, true
+	}
+	for key := range fields {
+		if strings.EqualFold(key, name) {
+			return key, true
+		}
+	}
+	return "", false
+}
+
+// parseSalesforceTime parses a datetime, date or time value returned by salesforce.
+func parseSalesforceTime(value string) (time.Time, error) {
+	for _, layout := range []string{salesforceDateTimeLayout, time.RFC3339Nano, salesforceDateLayout, salesforceTimeLayout} {
+		t, err := time.Parse(layout, value)
+		if err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q", value)
+}
@@ -0,0 +1,141 @@
+package simpleforce
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+)
+
+type testOwner struct {
+	Name  string
+	Email string `json:"Email"`
+}
+
+type testContact struct {
+	ID    string `sf:"Id"`
+	Email string
+}
+
+type testAccount struct {
+	ID             string        `sf:"Id"`
+	Name           string        `json:"name"`
+	OwnerName      string        `sf:"Owner.Name"`
+	Owner          *testOwner    `sf:"Owner"`
+	Contacts       []testContact `sf:"Contacts"`
+	Employees      int
+	AnnualRevenue  float64
+	IsPartner      bool
+	CreatedDate    time.Time
+	LastActivity   *time.Time `sf:"LastActivityDate"`
+	Regions        []string   `sf:"Regions__c"`
+	Raw            SObject    `sf:"Parent"`
+	Ignored        string     `sf:"-"`
+	MissingDefault string
+}
+
+const testAccountRecord = `{
+	"attributes": {"type": "Account", "url": "/services/data/v54.0/sobjects/Account/001000000000001AAA"},
+	"Id": "001000000000001AAA",
+	"Name": "Acme",
+	"Owner": {
+		"attributes": {"type": "User", "url": "/services/data/v54.0/sobjects/User/005000000000001AAA"},
+		"Name": "Owner Name",
+		"Email": "owner@example.com"
+	},
+	"Contacts": {
+		"totalSize": 2,
+		"done": true,
+		"records": [
+			{"attributes": {"type": "Contact"}, "Id": "003000000000001AAA", "Email": "a@example.com"},
+			{"attributes": {"type": "Contact"}, "Id": "003000000000002AAA", "Email": "b@example.com"}
+		]
+	},
+	"Employees": 42,
+	"AnnualRevenue": 1234.5,
+	"IsPartner": true,
+	"CreatedDate": "2024-01-02T03:04:05.000+0000",
+	"LastActivityDate": "2024-02-03",
+	"Regions__c": "EMEA;APAC",
+	"Parent": {"attributes": {"type": "Account"}, "Id": "001000000000002AAA"},
+	"Ignored": "should not be decoded",
+	"MissingDefault": null
+}`
+
+func TestSObject_Decode(t *testing.T) {
+	var obj SObject
+	if err := json.Unmarshal([]byte(testAccountRecord), &obj); err != nil {
+		t.Fatal(err)
+	}
+
+	account := testAccount{MissingDefault: "default"}
+	if err := obj.Decode(&account); err != nil {
+		t.Fatal(err)
+	}
+
+	if account.ID != "001000000000001AAA" || account.Name != "Acme" || account.Employees != 42 ||
+		account.AnnualRevenue != 1234.5 || !account.IsPartner {
+		t.Fatalf("unexpected scalar fields %+v", account)
+	}
+	if account.OwnerName != "Owner Name" || account.Owner == nil || account.Owner.Email != "owner@example.com" {
+		t.Fatalf("unexpected relationship fields %+v", account)
+	}
+	if len(account.Contacts) != 2 || account.Contacts[1].Email != "b@example.com" {
+		t.Fatalf("unexpected child records %+v", account.Contacts)
+	}
+	if !account.CreatedDate.Equal(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)) ||
+		account.LastActivity == nil || !account.LastActivity.Equal(time.Date(2024, 2, 3, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected dates %v, %v", account.CreatedDate, account.LastActivity)
+	}
+	if len(account.Regions) != 2 || account.Regions[1] != "APAC" {
+		t.Fatalf("unexpected multi-select picklist %v", account.Regions)
+	}
+	if account.Raw.ID() != "001000000000002AAA" || account.Ignored != "" || account.MissingDefault != "default" {
+		t.Fatalf("unexpected fields %+v", account)
+	}
+
+	// Negative: invalid targets and mismatching types.
+	if err := obj.Decode(account); err == nil {
+		t.Fatal("expected error for non-pointer target")
+	}
+	var mismatch struct {
+		Name int
+	}
+	if err := obj.Decode(&mismatch); err == nil {
+		t.Fatal("expected error for mismatching type")
+	}
+	var notImplemented struct {
+		Name fmt.Stringer
+	}
+	if err := obj.Decode(&notImplemented); err == nil {
+		t.Fatal("expected error for an interface the value doesn't implement")
+	}
+}
+
+func TestQueryAs(t *testing.T) {
+	ctx := context.Background()
+
+	server := newTestQueryServer(t, 5, 2)
+	client := newLoggedInTestClient(t, server)
+
+	type user struct {
+		ID   string `sf:"Id"`
+		Name string
+	}
+	users, err := QueryAs[user](ctx, client, "SELECT Id, Name FROM User")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != 5 || users[3].Name != "User 3" || users[3].ID != server.records[3]["Id"] {
+		t.Fatalf("unexpected users %+v", users)
+	}
+
+	pointers, err := QueryAs[*user](ctx, client, "SELECT Id, Name FROM User")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pointers) != 5 || pointers[4].Name != "User 4" {
+		t.Fatalf("unexpected users %+v", pointers)
+	}
+}
@@ -0,0 +1,232 @@
+package simpleforce
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// DescribeGlobalResult lists the SObject types available in the org, as returned by DescribeGlobalTyped.
+// Ref: https://developer.salesforce.com/docs/atlas.en-us.api.meta/api/sforce_api_calls_describeglobal_describeglobalresult.htm
+type DescribeGlobalResult struct {
+	Encoding     string                  `json:"encoding"`
+	MaxBatchSize int                     `json:"maxBatchSize"`
+	SObjects     []DescribeGlobalSObject `json:"sobjects"`
+}
+
+// DescribeGlobalSObject summarizes the metadata of an SObject type in the global describe.
+type DescribeGlobalSObject struct {
+	Name                string            `json:"name"`
+	Label               string            `json:"label"`
+	LabelPlural         string            `json:"labelPlural"`
+	KeyPrefix           string            `json:"keyPrefix"`
+	Custom              bool              `json:"custom"`
+	CustomSetting       bool              `json:"customSetting"`
+	Createable          bool              `json:"createable"`
+	Updateable          bool              `json:"updateable"`
+	Deletable           bool              `json:"deletable"`
+	Undeletable         bool              `json:"undeletable"`
+	Queryable           bool              `json:"queryable"`
+	Retrieveable        bool              `json:"retrieveable"`
+	Searchable          bool              `json:"searchable"`
+	Replicateable       bool              `json:"replicateable"`
+	Triggerable         bool              `json:"triggerable"`
+	Layoutable          bool              `json:"layoutable"`
+	Mergeable           bool              `json:"mergeable"`
+	URLs                map[string]string `json:"urls"`
+	DeprecatedAndHidden bool              `json:"deprecatedAndHidden"`
+}
+
+// DescribeSObjectResult describes the metadata of an SObject type, as returned by DescribeSObject.
+// Ref: https://developer.salesforce.com/docs/atlas.en-us.api.meta/api/sforce_api_calls_describesobjects_describesobjectresult.htm
+type DescribeSObjectResult struct {
+	Name                string              `json:"name"`
+	Label               string              `json:"label"`
+	LabelPlural         string              `json:"labelPlural"`
+	KeyPrefix           string              `json:"keyPrefix"`
+	Custom              bool                `json:"custom"`
+	CustomSetting       bool                `json:"customSetting"`
+	Createable          bool                `json:"createable"`
+	Updateable          bool                `json:"updateable"`
+	Deletable           bool                `json:"deletable"`
+	Undeletable         bool                `json:"undeletable"`
+	Queryable           bool                `json:"queryable"`
+	Retrieveable        bool                `json:"retrieveable"`
+	Searchable          bool                `json:"searchable"`
+	Replicateable       bool                `json:"replicateable"`
+	Fields              []DescribeField     `json:"fields"`
+	ChildRelationships  []ChildRelationship `json:"childRelationships"`
+	RecordTypeInfos     []RecordTypeInfo    `json:"recordTypeInfos"`
+	URLs                map[string]string   `json:"urls"`
+	DeprecatedAndHidden bool                `json:"deprecatedAndHidden"`
+}
+
+// DescribeField describes a field of an SObject type.
+// Ref: https://developer.salesforce.com/docs/atlas.en-us.api.meta/api/sforce_api_calls_describesobjects_describesobjectresult.htm#field
+type DescribeField struct {
+	Name     string `json:"name"`
+	Label    string `json:"label"`
+	Type     string `json:"type"`
+	SOAPType string `json:"soapType"`
+	// Length is the maximum number of characters of string fields.
+	Length     int `json:"length"`
+	ByteLength int `json:"byteLength"`
+	// Digits is the maximum number of digits of integer fields.
+	Digits int `json:"digits"`
+	// Precision and Scale are the number of digits and of decimal places of double, currency and percent fields.
+	Precision         int             `json:"precision"`
+	Scale             int             `json:"scale"`
+	Nillable          bool            `json:"nillable"`
+	Createable        bool            `json:"createable"`
+	Updateable        bool            `json:"updateable"`
+	DefaultedOnCreate bool            `json:"defaultedOnCreate"`
+	DefaultValue      interface{}     `json:"defaultValue"`
+	Custom            bool            `json:"custom"`
+	Unique            bool            `json:"unique"`
+	ExternalID        bool            `json:"externalId"`
+	IDLookup          bool            `json:"idLookup"`
+	NameField         bool            `json:"nameField"`
+	Calculated        bool            `json:"calculated"`
+	CalculatedFormula string          `json:"calculatedFormula"`
+	Filterable        bool            `json:"filterable"`
+	Sortable          bool            `json:"sortable"`
+	Groupable         bool            `json:"groupable"`
+	PicklistValues    []PicklistValue `json:"picklistValues"`
+	DependentPicklist bool            `json:"dependentPicklist"`
+	ControllerName    string          `json:"controllerName"`
+	// ReferenceTo lists the SObject types a reference field may point to.
+	ReferenceTo []string `json:"referenceTo"`
+	// RelationshipName is the name used to access the referenced object in SOQL, e.g. Owner for OwnerId.
+	RelationshipName    string `json:"relationshipName"`
+	InlineHelpText      string `json:"inlineHelpText"`
+	HTMLFormatted       bool   `json:"htmlFormatted"`
+	DeprecatedAndHidden bool   `json:"deprecatedAndHidden"`
+}
+
+// PicklistValue is a value of a picklist field.
+type PicklistValue struct {
+	Value        string `json:"value"`
+	Label        string `json:"label"`
+	Active       bool   `json:"active"`
+	DefaultValue bool   `json:"defaultValue"`
+	// ValidFor is the base64 encoded bitmap of the values of the controlling field this value is valid for.
+	ValidFor string `json:"validFor"`
+}
+
+// ChildRelationship describes a relationship from another SObject type to the described one.
+type ChildRelationship struct {
+	ChildSObject string `json:"childSObject"`
+	Field        string `json:"field"`
+	// RelationshipName is the name of the relationship in subqueries, e.g. Contacts for Account. It is empty if the
+	// relationship can't be queried.
+	RelationshipName    string `json:"relationshipName"`
+	CascadeDelete       bool   `json:"cascadeDelete"`
+	RestrictedDelete    bool   `json:"restrictedDelete"`
+	DeprecatedAndHidden bool   `json:"deprecatedAndHidden"`
+}
+
+// RecordTypeInfo describes a record type of an SObject type.
+type RecordTypeInfo struct {
+	RecordTypeID             string `json:"recordTypeId"`
+	Name                     string `json:"name"`
+	DeveloperName            string `json:"developerName"`
+	Active                   bool   `json:"active"`
+	Available                bool   `json:"available"`
+	DefaultRecordTypeMapping bool   `json:"defaultRecordTypeMapping"`
+	Master                   bool   `json:"master"`
+}
+
+// DescribeSObject queries the metadata of the SObject type typeName using the "describe" API. Unlike SObject.Describe,
+// errors are returned, e.g. a SalesforceError with the code NOT_FOUND if the type doesn't exist.
+// Ref: https://developer.salesforce.com/docs/atlas.en-us.api_rest.meta/api_rest/resources_sobject_describe.htm
+func (client *Client) DescribeSObject(ctx context.Context, typeName string) (*DescribeSObjectResult, error) {
+	data, err := client.describe(ctx, typeName)
+	if err != nil {
+		return nil, err
+	}
+
+	var result DescribeSObjectResult
+	err = json.Unmarshal(data, &result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse describe result: %w", err)
+	}
+	return &result, nil
+}
+
+// DescribeSObject queries the metadata of the type of the SObject, as done by Client.DescribeSObject.
+func (obj *SObject) DescribeSObject(ctx context.Context) (*DescribeSObjectResult, error) {
+	if obj.Type() == "" || obj.client() == nil {
+		// Sanity check.
+		return nil, ErrNoTypeIdClientOrId
+	}
+	return obj.client().DescribeSObject(ctx, obj.Type())
+}
+
+// DescribeGlobalTyped lists the SObject types available in the org using the global describe, like DescribeGlobal but
+// with a typed result. A SalesforceError is returned on failure.
+// Ref: https://developer.salesforce.com/docs/atlas.en-us.api_rest.meta/api_rest/resources_describeGlobal.htm
+func (client *Client) DescribeGlobalTyped(ctx context.Context) (*DescribeGlobalResult, error) {
+	data, err := client.describeGlobal(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var result DescribeGlobalResult
+	err = json.Unmarshal(data, &result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse global describe result: %w", err)
+	}
+	return &result, nil
+}
+
+// SObject returns the SObject type name, matching it case-insensitively, or nil if there is no such type.
+func (result *DescribeGlobalResult) SObject(name string) *DescribeGlobalSObject {
+	for i := range result.SObjects {
+		if strings.EqualFold(result.SObjects[i].Name, name) {
+			return &result.SObjects[i]
+		}
+	}
+	return nil
+}
+
+// Field returns the field name, matching it case-insensitively, or nil if there is no such field.
+func (result *DescribeSObjectResult) Field(name string) *DescribeField {
+	for i := range result.Fields {
+		if strings.EqualFold(result.Fields[i].Name, name) {
+			return &result.Fields[i]
+		}
+	}
+	return nil
+}
+
+// ChildRelationship returns the child relationship named name, matching it case-insensitively, or nil if there is no
+// such relationship.
+func (result *DescribeSObjectResult) ChildRelationship(name string) *ChildRelationship {
+	for i := range result.ChildRelationships {
+		if strings.EqualFold(result.ChildRelationships[i].RelationshipName, name) {
+			return &result.ChildRelationships[i]
+		}
+	}
+	return nil
+}
+
+// describeGlobal requests the global describe result.
+func (client *Client) describeGlobal(ctx context.Context) ([]byte, error) {
+	if !client.isLoggedIn() {
+		return nil, ErrAuthentication
+	}
+	return client.getDescribe(ctx, "sobjects")
+}
+
+// describe requests the describe result of the SObject type typeName.
+func (client *Client) describe(ctx context.Context, typeName string) ([]byte, error) {
+	if !client.isLoggedIn() {
+		return nil, ErrAuthentication
+	}
+	if typeName == "" {
+		return nil, ErrNoTypeIdClientOrId
+	}
+
+	return client.getDescribe(ctx, "sobjects/"+typeName+"/describe")
+}
@@ -0,0 +1,181 @@
+package simpleforce
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+const testDescribeUser = `{
+	"name": "User",
+	"label": "User",
+	"labelPlural": "Users",
+	"keyPrefix": "005",
+	"custom": false,
+	"createable": true,
+	"updateable": true,
+	"deletable": false,
+	"queryable": true,
+	"fields": [
+		{"name": "Id", "type": "id", "length": 18, "nillable": false, "createable": false, "updateable": false, "idLookup": true},
+		{"name": "Email", "type": "email", "length": 128, "nillable": false, "createable": true, "updateable": true},
+		{
+			"name": "LanguageLocaleKey", "type": "picklist", "length": 40, "nillable": false,
+			"picklistValues": [
+				{"value": "en_US", "label": "English", "active": true, "defaultValue": true},
+				{"value": "de", "label": "German", "active": true, "defaultValue": false}
+			]
+		},
+		{"name": "ManagerId", "type": "reference", "length": 18, "nillable": true, "referenceTo": ["User"], "relationshipName": "Manager"},
+		{"name": "Federation_ID__c", "type": "string", "length": 255, "nillable": true, "custom": true, "externalId": true, "unique": true}
+	],
+	"childRelationships": [
+		{"childSObject": "PermissionSetAssignment", "field": "AssigneeId", "relationshipName": "PermissionSetAssignments", "cascadeDelete": true}
+	],
+	"recordTypeInfos": [
+		{"recordTypeId": "012000000000000AAA", "name": "Master", "developerName": "Master", "active": true, "available": true, "defaultRecordTypeMapping": true, "master": true}
+	],
+	"urls": {"describe": "/services/data/v54.0/sobjects/User/describe"}
+}`
+
+func newTestDescribeServer(t *testing.T) *http.ServeMux {
+	prefix := "/services/data/v" + DefaultAPIVersion + "/sobjects/"
+	mux := http.NewServeMux()
+	mux.HandleFunc(prefix+"User/describe", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(testDescribeUser))
+	})
+	mux.HandleFunc("/services/data/v"+DefaultAPIVersion+"/sobjects", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer __SESSION_ID__" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`[{"message":"Session expired or invalid","errorCode":"INVALID_SESSION_ID"}]`))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"encoding": "UTF-8",
+			"maxBatchSize": 200,
+			"sobjects": [
+				{"name": "Account", "label": "Account", "keyPrefix": "001", "queryable": true, "createable": true, "custom": false},
+				{"name": "Invoice__c", "label": "Invoice", "keyPrefix": "a00", "queryable": true, "custom": true},
+				{"name": "AccountFeed", "label": "Account Feed", "keyPrefix": null, "queryable": true}
+			]
+		}`))
+	})
+	mux.HandleFunc(prefix+"Unknown/describe", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`[{"message":"The requested resource does not exist","errorCode":"NOT_FOUND"}]`))
+	})
+	return mux
+}
+
+func TestClient_DescribeSObject(t *testing.T) {
+	ctx := context.Background()
+
+	client := newLoggedInTestClient(t, newTestDescribeServer(t))
+
+	result, err := client.DescribeSObject(ctx, "User")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Name != "User" || result.KeyPrefix != "005" || !result.Queryable || result.Deletable || len(result.Fields) != 5 {
+		t.Fatalf("unexpected result %+v", result)
+	}
+
+	email := result.Field("email")
+	if email == nil || email.Type != "email" || email.Length != 128 || email.Nillable || !email.Updateable {
+		t.Fatalf("unexpected field %+v", email)
+	}
+	language := result.Field("LanguageLocaleKey")
+	if language == nil || len(language.PicklistValues) != 2 || !language.PicklistValues[0].DefaultValue ||
+		language.PicklistValues[1].Value != "de" {
+		t.Fatalf("unexpected field %+v", language)
+	}
+	manager := result.Field("ManagerId")
+	if manager == nil || manager.ReferenceTo[0] != "User" || manager.RelationshipName != "Manager" {
+		t.Fatalf("unexpected field %+v", manager)
+	}
+	if federationID := result.Field("Federation_ID__c"); federationID == nil || !federationID.ExternalID || !federationID.Custom {
+		t.Fatalf("unexpected field %+v", federationID)
+	}
+	if result.Field("Missing") != nil {
+		t.Fatal("expected no field")
+	}
+
+	assignments := result.ChildRelationship("PermissionSetAssignments")
+	if assignments == nil || assignments.ChildSObject != "PermissionSetAssignment" || !assignments.CascadeDelete {
+		t.Fatalf("unexpected child relationship %+v", assignments)
+	}
+	if len(result.RecordTypeInfos) != 1 || !result.RecordTypeInfos[0].Master {
+		t.Fatalf("unexpected record types %+v", result.RecordTypeInfos)
+	}
+
+	// The SObject forms return the same metadata.
+	if result, err := client.SObject("User").DescribeSObject(ctx); err != nil || result.LabelPlural != "Users" {
+		t.Fatalf("unexpected result %+v, %v", result, err)
+	}
+	if meta := client.SObject("User").Describe(ctx); meta == nil || (*meta)["keyPrefix"] != "005" {
+		t.Fatalf("unexpected metadata %v", meta)
+	}
+
+	// Negative: unknown object.
+	var sfErr SalesforceError
+	if _, err := client.DescribeSObject(ctx, "Unknown"); !errors.As(err, &sfErr) || sfErr.ErrorCode != "NOT_FOUND" {
+		t.Fatalf("expected NOT_FOUND, got %v", err)
+	}
+	if meta := client.SObject("Unknown").Describe(ctx); meta != nil {
+		t.Fatalf("expected no metadata, got %v", meta)
+	}
+	if _, err := client.SObject().DescribeSObject(ctx); !errors.Is(err, ErrNoTypeIdClientOrId) {
+		t.Fatalf("expected ErrNoTypeIdClientOrId, got %v", err)
+	}
+}
+
+func TestClient_DescribeGlobalTyped(t *testing.T) {
+	ctx := context.Background()
+
+	client := newLoggedInTestClient(t, newTestDescribeServer(t))
+	// The global describe is served by the instance, not the login host.
+	client.baseURL = "http://login.invalid"
+
+	result, err := client.DescribeGlobalTyped(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Encoding != "UTF-8" || result.MaxBatchSize != 200 || len(result.SObjects) != 3 {
+		t.Fatalf("unexpected result %+v", result)
+	}
+	invoice := result.SObject("invoice__c")
+	if invoice == nil || invoice.KeyPrefix != "a00" || !invoice.Custom || !invoice.Queryable || invoice.Createable {
+		t.Fatalf("unexpected sobject %+v", invoice)
+	}
+	if feed := result.SObject("AccountFeed"); feed == nil || feed.KeyPrefix != "" {
+		t.Fatalf("unexpected sobject %+v", feed)
+	}
+	if result.SObject("Missing") != nil {
+		t.Fatal("expected no sobject")
+	}
+
+	meta, err := client.DescribeGlobal(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sobjects, ok := (*meta)["sobjects"].([]interface{}); !ok || len(sobjects) != 3 {
+		t.Fatalf("unexpected metadata %v", meta)
+	}
+
+	// Negative: failures are reported.
+	client.SetSidLoc("__EXPIRED_SESSION_ID__", client.instanceURL)
+	var sfErr SalesforceError
+	if _, err := client.DescribeGlobalTyped(ctx); !errors.As(err, &sfErr) || sfErr.ErrorCode != "INVALID_SESSION_ID" {
+		t.Fatalf("expected INVALID_SESSION_ID, got %v", err)
+	}
+	if _, err := client.DescribeGlobal(ctx); !errors.As(err, &sfErr) {
+		t.Fatalf("expected SalesforceError, got %v", err)
+	}
+	client.SetSidLoc("", client.instanceURL)
+	if _, err := client.DescribeGlobal(ctx); !errors.Is(err, ErrAuthentication) {
+		t.Fatalf("expected ErrAuthentication, got %v", err)
+	}
+}
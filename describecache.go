@@ -0,0 +1,178 @@
+package simpleforce
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// DescribeCacheEntry is a describe result as persisted by a DescribeCache.
+type DescribeCacheEntry struct {
+	// Data is the response body of the describe request.
+	Data []byte `json:"data"`
+	// LastModified is the Last-Modified header of the response, if any.
+	LastModified string `json:"last_modified,omitempty"`
+	// FetchedAt is the time the result was fetched, or last confirmed to be unchanged.
+	FetchedAt time.Time `json:"fetched_at"`
+}
+
+// DescribeCache persists describe results, so that unchanged metadata doesn't need to be fetched again.
+type DescribeCache interface {
+	// Load returns the entry stored under key, or nil if there is none.
+	Load(ctx context.Context, key string) (*DescribeCacheEntry, error)
+	// Save stores entry under key, replacing any existing entry.
+	Save(ctx context.Context, key string, entry *DescribeCacheEntry) error
+}
+
+// UseDescribeCache makes the client cache the results of the global describe and of the describes of SObject types in
+// cache, keyed by instance, API version and type. Cached results younger than ttl are used without any request. Older
+// results are revalidated with a conditional request, which costs an API call but no download if the metadata didn't
+// change. A nil cache disables caching.
+// Ref: https://developer.salesforce.com/docs/atlas.en-us.api_rest.meta/api_rest/headers_ifmodifiedsince.htm
+func (client *Client) UseDescribeCache(cache DescribeCache, ttl time.Duration) {
+	client.describeCache = cache
+	client.describeCacheTTL = ttl
+}
+
+// getDescribe requests the describe resource path, relative to the REST API, through the describe cache if any.
+func (client *Client) getDescribe(ctx context.Context, path string) ([]byte, error) {
+	l := ctxzap.Extract(ctx)
+
+	u := client.makeURL(path)
+	cache := client.describeCache
+	if cache == nil {
+		return client.httpRequest(ctx, http.MethodGet, u, nil)
+	}
+
+	// The URL identifies the instance, the API version and the resource.
+	key := u
+	entry, err := cache.Load(ctx, key)
+	if err != nil {
+		l.Warn("failed to load cached describe result", zap.String("url", u), zap.Error(err))
+		entry = nil
+	}
+	if entry != nil && time.Since(entry.FetchedAt) < client.describeCacheTTL {
+		return entry.Data, nil
+	}
+
+	var opts []CallOption
+	if entry != nil {
+		since := entry.LastModified
+		if since == "" {
+			since = entry.FetchedAt.UTC().Format(http.TimeFormat)
+		}
+		opts = append(opts, WithHeader("If-Modified-Since", since))
+	}
+
+	data, resp, err := client.httpRequestResponse(ctx, http.MethodGet, u, nil, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusNotModified && entry != nil {
+		entry.FetchedAt = time.Now()
+	} else {
+		entry = &DescribeCacheEntry{
+			Data:         data,
+			LastModified: resp.Header.Get("Last-Modified"),
+			FetchedAt:    time.Now(),
+		}
+	}
+
+	// The result is usable even if it can't be cached.
+	err = cache.Save(ctx, key, entry)
+	if err != nil {
+		l.Warn("failed to cache describe result", zap.String("url", u), zap.Error(err))
+	}
+	return entry.Data, nil
+}
+
+// MemoryDescribeCache is a DescribeCache keeping describe results in memory, to share them between clients of one
+// process.
+type MemoryDescribeCache struct {
+	mu      sync.Mutex
+	entries map[string]DescribeCacheEntry
+}
+
+// NewMemoryDescribeCache creates an empty MemoryDescribeCache.
+func NewMemoryDescribeCache() *MemoryDescribeCache {
+	return &MemoryDescribeCache{entries: make(map[string]DescribeCacheEntry)}
+}
+
+// Load returns the entry stored under key, or nil if there is none.
+func (cache *MemoryDescribeCache) Load(_ context.Context, key string) (*DescribeCacheEntry, error) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	entry, ok := cache.entries[key]
+	if !ok {
+		return nil, nil
+	}
+	return &entry, nil
+}
+
+// Save stores entry under key.
+func (cache *MemoryDescribeCache) Save(_ context.Context, key string, entry *DescribeCacheEntry) error {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	cache.entries[key] = *entry
+	return nil
+}
+
+// FileDescribeCache is a DescribeCache keeping describe results in a directory, one file per key, so that they
+// survive restarts and can be shared by processes on the same host.
+type FileDescribeCache struct {
+	dir string
+}
+
+// NewFileDescribeCache creates a FileDescribeCache writing to dir, which is created if needed.
+func NewFileDescribeCache(dir string) (*FileDescribeCache, error) {
+	err := os.MkdirAll(dir, 0o700)
+	if err != nil {
+		return nil, err
+	}
+	return &FileDescribeCache{dir: dir}, nil
+}
+
+// Load returns the entry stored under key, or nil if there is none.
+func (cache *FileDescribeCache) Load(_ context.Context, key string) (*DescribeCacheEntry, error) {
+	data, err := os.ReadFile(cache.path(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var entry DescribeCacheEntry
+	err = json.Unmarshal(data, &entry)
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// Save stores entry under key, replacing the file atomically.
+func (cache *FileDescribeCache) Save(_ context.Context, key string, entry *DescribeCacheEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomic(cache.dir, hashedFileName(key, ".json"), data)
+}
+
+// path returns the file holding the entry of key.
+func (cache *FileDescribeCache) path(key string) string {
+	return filepath.Join(cache.dir, hashedFileName(key, ".json"))
+}
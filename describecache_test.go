@@ -0,0 +1,163 @@
+package simpleforce
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testDescribeCacheServer serves describe results with a Last-Modified header, answering conditional requests for
+// unchanged metadata with 304 Not Modified.
+type testDescribeCacheServer struct {
+	mu           sync.Mutex
+	lastModified time.Time
+	label        string
+	requests     int
+	notModified  int
+}
+
+func (s *testDescribeCacheServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests++
+
+	if since, err := http.ParseTime(r.Header.Get("If-Modified-Since")); err == nil && !s.lastModified.After(since) {
+		s.notModified++
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Last-Modified", s.lastModified.UTC().Format(http.TimeFormat))
+	w.Header().Set("Content-Type", "application/json")
+	switch r.URL.Path {
+	case "/services/data/v" + DefaultAPIVersion + "/sobjects/User/describe":
+		_, _ = w.Write([]byte(`{"name": "User", "label": "` + s.label + `", "keyPrefix": "005"}`))
+	case "/services/data/v" + DefaultAPIVersion + "/sobjects":
+		_, _ = w.Write([]byte(`{"encoding": "UTF-8", "maxBatchSize": 200, "sobjects": []}`))
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (s *testDescribeCacheServer) counts() (int, int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests, s.notModified
+}
+
+func (s *testDescribeCacheServer) change(label string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.label = label
+	s.lastModified = s.lastModified.Add(time.Hour)
+}
+
+func TestClient_DescribeCache(t *testing.T) {
+	ctx := context.Background()
+
+	server := &testDescribeCacheServer{lastModified: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), label: "User"}
+	client := newLoggedInTestClient(t, server)
+	cache := NewMemoryDescribeCache()
+
+	// Within the TTL, cached results are used without any request.
+	client.UseDescribeCache(cache, time.Hour)
+	for i := 0; i < 3; i++ {
+		result, err := client.DescribeSObject(ctx, "User")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.Label != "User" {
+			t.Fatalf("unexpected result %+v", result)
+		}
+	}
+	if requests, _ := server.counts(); requests != 1 {
+		t.Fatalf("expected a single request, got %d", requests)
+	}
+
+	// Once expired, cached results are revalidated.
+	client.UseDescribeCache(cache, 0)
+	if meta := client.SObject("User").Describe(ctx); meta == nil || (*meta)["label"] != "User" {
+		t.Fatalf("unexpected metadata %v", meta)
+	}
+	if requests, notModified := server.counts(); requests != 2 || notModified != 1 {
+		t.Fatalf("expected a conditional request, got %d requests, %d not modified", requests, notModified)
+	}
+
+	// Changed metadata is fetched again.
+	server.change("Person")
+	result, err := client.DescribeSObject(ctx, "User")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Label != "Person" {
+		t.Fatalf("unexpected result %+v", result)
+	}
+	entry, err := cache.Load(ctx, client.makeURL("sobjects/User/describe"))
+	if err != nil || entry == nil || entry.LastModified != "Mon, 01 Jan 2024 01:00:00 GMT" {
+		t.Fatalf("unexpected cache entry %+v, %v", entry, err)
+	}
+
+	// The global describe is cached too.
+	for i := 0; i < 2; i++ {
+		if _, err := client.DescribeGlobalTyped(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if requests, notModified := server.counts(); requests != 5 || notModified != 2 {
+		t.Fatalf("unexpected %d requests, %d not modified", requests, notModified)
+	}
+}
+
+func TestClient_NotModifiedUnconditional(t *testing.T) {
+	ctx := context.Background()
+
+	client := newLoggedInTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotModified)
+	}))
+
+	// Negative: only conditional requests may return Not Modified.
+	if _, err := client.ApexREST(ctx, http.MethodGet, "services/apexrest/users", nil); err == nil {
+		t.Fatal("expected error for an unconditional request answered with 304")
+	}
+}
+
+func TestFileDescribeCache(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	server := &testDescribeCacheServer{lastModified: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), label: "User"}
+	cache, err := NewFileDescribeCache(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry, err := cache.Load(ctx, "missing"); err != nil || entry != nil {
+		t.Fatalf("expected no entry, got %v, %v", entry, err)
+	}
+
+	client := newLoggedInTestClient(t, server)
+	client.UseDescribeCache(cache, time.Hour)
+	if _, err := client.DescribeSObject(ctx, "User"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Another client, as in another process, uses the results cached on disk.
+	reopened, err := NewFileDescribeCache(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other := newLoggedInTestClient(t, server)
+	other.SetSidLoc("__SESSION_ID__", client.instanceURL)
+	other.UseDescribeCache(reopened, time.Hour)
+	result, err := other.DescribeSObject(ctx, "User")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.KeyPrefix != "005" {
+		t.Fatalf("unexpected result %+v", result)
+	}
+	if requests, _ := server.counts(); requests != 1 {
+		t.Fatalf("expected a single request, got %d", requests)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
 	"go.uber.org/zap"
 	"net/http"
+	"strings"
 
 	"errors"
 )
@@ -24,6 +25,16 @@ var (
 	ErrNoTypeIdClientOrId = errors.New("sObject has no type id, client or id")
 
 	ErrOidNotFound = errors.New("oid not found")
+
+	// ErrQueryLocatorExpired is returned when the query locator of a query cursor or iterator has expired, in which
+	// case the query must be run again.
+	ErrQueryLocatorExpired = errors.New("query locator expired")
+
+	// ErrInvalidID is returned when a string is not a valid 15 or 18 character salesforce ID.
+	ErrInvalidID = errors.New("invalid ID")
+
+	// ErrUnknownKeyPrefix is returned when the key prefix of an ID doesn't belong to any SObject type of the org.
+	ErrUnknownKeyPrefix = errors.New("unknown key prefix")
 )
 
 type jsonError []struct {
@@ -31,9 +42,21 @@ type jsonError []struct {
 	ErrorCode string `json:"errorCode"`
 }
 
+type oauthError struct {
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
 type xmlError struct {
 	Message   string `xml:"Body>Fault>faultstring"`
 	ErrorCode string `xml:"Body>Fault>faultcode"`
+	// Detail holds the API fault (e.g. LoginFault) describing the exception, if any.
+	Detail struct {
+		Fault struct {
+			ExceptionCode    string `xml:"exceptionCode"`
+			ExceptionMessage string `xml:"exceptionMessage"`
+		} `xml:",any"`
+	} `xml:"Body>Fault>detail"`
 }
 
 type SalesforceError struct {
@@ -63,17 +86,41 @@ func ParseSalesforceError(statusCode int, responseBody []byte) (err error) {
 		}
 	}
 
+	oauthError := oauthError{}
+	err = json.Unmarshal(responseBody, &oauthError)
+	if err == nil && oauthError.Error != "" {
+		return SalesforceError{
+			Message: fmt.Sprintf(
+				logPrefix+" Error. http code: %v Error Message:  %v Error Code: %v",
+				statusCode, oauthError.ErrorDescription, oauthError.Error,
+			),
+			HttpCode:     statusCode,
+			ErrorCode:    oauthError.Error,
+			ErrorMessage: oauthError.ErrorDescription,
+		}
+	}
+
 	xmlError := xmlError{}
 	err = xml.Unmarshal(responseBody, &xmlError)
 	if err == nil {
+		// Prefer the exception of the fault detail; otherwise strip the namespace from fault codes like "sf:INVALID_LOGIN".
+		errorCode := xmlError.Detail.Fault.ExceptionCode
+		if errorCode == "" {
+			errorCode = xmlError.ErrorCode[strings.Index(xmlError.ErrorCode, ":")+1:]
+		}
+		errorMessage := xmlError.Detail.Fault.ExceptionMessage
+		if errorMessage == "" {
+			errorMessage = xmlError.Message
+		}
+
 		return SalesforceError{
 			Message: fmt.Sprintf(
 				logPrefix+" Error. http code: %v Error Message:  %v Error Code: %v",
-				statusCode, xmlError.Message, xmlError.ErrorCode,
+				statusCode, errorMessage, errorCode,
 			),
 			HttpCode:     statusCode,
-			ErrorCode:    xmlError.ErrorCode,
-			ErrorMessage: xmlError.Message,
+			ErrorCode:    errorCode,
+			ErrorMessage: errorMessage,
 		}
 	}
 
@@ -108,3 +155,21 @@ func parseUhttpError(ctx context.Context, resp *http.Response, errHttp error) er
 
 	return errHttp
 }
+
+// isInvalidSession returns if err reports an expired or otherwise invalid session.
+func isInvalidSession(err error) bool {
+	var sfErr SalesforceError
+	if !errors.As(err, &sfErr) {
+		return false
+	}
+	return sfErr.ErrorCode == "INVALID_SESSION_ID" || sfErr.HttpCode == http.StatusUnauthorized
+}
+
+// isInvalidQueryLocator returns if err reports an expired or otherwise invalid query locator.
+func isInvalidQueryLocator(err error) bool {
+	var sfErr SalesforceError
+	if !errors.As(err, &sfErr) {
+		return false
+	}
+	return sfErr.ErrorCode == "INVALID_QUERY_LOCATOR"
+}
@@ -42,6 +42,39 @@ func TestSuccessfulXMLParse(t *testing.T) {
 	}
 }
 
+func TestSuccessfulSOAPFaultParse(t *testing.T) {
+	response := `<?xml version="1.0" encoding="UTF-8"?>
+		<soapenv:Envelope xmlns:soapenv="http://schemas.xmlsoap.org/soap/envelope/"
+			xmlns:sf="urn:fault.partner.soap.sforce.com" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance">
+			<soapenv:Body>
+				<soapenv:Fault>
+					<faultcode>sf:SMTH_WRNG</faultcode>
+					<faultstring>SMTH_WRNG: something else went wrong</faultstring>
+					<detail>
+						<sf:LoginFault xsi:type="sf:LoginFault">
+							<sf:exceptionCode>SMTH_WRNG</sf:exceptionCode>
+							<sf:exceptionMessage>something went wrong</sf:exceptionMessage>
+						</sf:LoginFault>
+					</detail>
+				</soapenv:Fault>
+			</soapenv:Body>
+		</soapenv:Envelope>
+	`
+	err := ParseSalesforceError(417, []byte(response))
+	if err != expectedError {
+		t.Errorf("failed to parse SOAP fault, got %s", err)
+	}
+}
+
+func TestSuccessfulOAuthParse(t *testing.T) {
+	response := `{"error": "SMTH_WRNG", "error_description": "something went wrong"}`
+
+	err := ParseSalesforceError(417, []byte(response))
+	if err != expectedError {
+		t.Errorf("failed to parse OAuth error, got %s", err)
+	}
+}
+
 func TestUnsuccessfulParse(t *testing.T) {
 	response := "surprise!"
 	unknownError := SalesforceError{
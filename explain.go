@@ -0,0 +1,84 @@
+package simpleforce
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// ExplainResult holds the query plans of an SOQL query, list view or report, as returned by Explain.
+type ExplainResult struct {
+	// Plans are sorted by RelativeCost, the first plan being the one the query optimizer uses.
+	Plans []QueryPlan `json:"plans"`
+	// SourceQuery is the SOQL query of a list view or report.
+	SourceQuery string `json:"sourceQuery,omitempty"`
+}
+
+// QueryPlan is a plan the query optimizer considered to run a query.
+type QueryPlan struct {
+	// Cardinality is the estimated number of records the leading operation returns.
+	Cardinality int `json:"cardinality"`
+	// Fields are the indexed fields used by the leading operation, if it uses an index.
+	Fields []string `json:"fields"`
+	// LeadingOperationType is the primary operation of the plan: Index, Other, Sharing or TableScan.
+	LeadingOperationType string `json:"leadingOperationType"`
+	// Notes explain why filters were not used for optimization.
+	Notes []QueryPlanNote `json:"notes"`
+	// RelativeCost is the cost of the plan compared to the selectivity threshold. Plans above 1 aren't selective.
+	RelativeCost float64 `json:"relativeCost"`
+	// SObjectCardinality is the approximate number of records of the object.
+	SObjectCardinality int `json:"sobjectCardinality"`
+	// SObjectType is the object the plan queries.
+	SObjectType string `json:"sobjectType"`
+}
+
+// QueryPlanNote explains why a filter of the query was not used for optimization.
+type QueryPlanNote struct {
+	Description   string   `json:"description"`
+	Fields        []string `json:"fields"`
+	TableEnumOrID string   `json:"tableEnumOrId"`
+}
+
+// Explain returns the query plans for q, which is either an SOQL query, a list view ID or a report ID, without running
+// the query.
+// Ref: https://developer.salesforce.com/docs/atlas.en-us.api_rest.meta/api_rest/dome_query_explain.htm
+func (client *Client) Explain(ctx context.Context, q string) (*ExplainResult, error) {
+	if !client.isLoggedIn() {
+		return nil, ErrAuthentication
+	}
+
+	u := client.makeURL("query?explain=" + url.QueryEscape(q))
+	data, err := client.httpRequest(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var result ExplainResult
+	err = json.Unmarshal(data, &result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse query plans: %w", err)
+	}
+
+	return &result, nil
+}
+
+// Plan returns the plan the query optimizer uses, i.e. the one with the lowest cost. ok is false if there is none.
+func (result *ExplainResult) Plan() (plan QueryPlan, ok bool) {
+	if len(result.Plans) == 0 {
+		return QueryPlan{}, false
+	}
+	return result.Plans[0], true
+}
+
+// Selective reports whether the query is selective, i.e. its best plan costs less than the selectivity threshold.
+func (result *ExplainResult) Selective() bool {
+	plan, ok := result.Plan()
+	return ok && plan.Selective()
+}
+
+// Selective reports whether the plan costs less than the selectivity threshold.
+func (plan QueryPlan) Selective() bool {
+	return plan.RelativeCost < 1
+}
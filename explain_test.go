@@ -0,0 +1,81 @@
+package simpleforce
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+const testExplainResponse = `{
+	"plans": [
+		{
+			"cardinality": 1,
+			"fields": ["Email"],
+			"leadingOperationType": "Index",
+			"notes": [],
+			"relativeCost": 0.0023,
+			"sobjectCardinality": 120000,
+			"sobjectType": "User"
+		},
+		{
+			"cardinality": 120000,
+			"fields": [],
+			"leadingOperationType": "TableScan",
+			"notes": [
+				{"description": "Not considering filter for optimization because unindexed", "fields": ["IsActive"], "tableEnumOrId": "User"}
+			],
+			"relativeCost": 2.87,
+			"sobjectCardinality": 120000,
+			"sobjectType": "User"
+		}
+	]
+}`
+
+func TestClient_Explain(t *testing.T) {
+	ctx := context.Background()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/services/data/v"+DefaultAPIVersion+"/query", func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("explain") {
+		case "SELECT Id FROM User WHERE Email = 'jdoe@example.com' AND IsActive = true":
+			_, _ = w.Write([]byte(testExplainResponse))
+		case "00B000000000001AAA":
+			_, _ = w.Write([]byte(`{"plans": [{"cardinality": 900, "leadingOperationType": "TableScan", "relativeCost": 1.2, "sobjectType": "Account"}], "sourceQuery": "SELECT Id FROM Account"}`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`[{"message":"unexpected token","errorCode":"MALFORMED_QUERY"}]`))
+		}
+	})
+	client := newLoggedInTestClient(t, mux)
+
+	result, err := client.Explain(ctx, "SELECT Id FROM User WHERE Email = 'jdoe@example.com' AND IsActive = true")
+	if err != nil {
+		t.Fatal(err)
+	}
+	plan, ok := result.Plan()
+	if !ok || plan.LeadingOperationType != "Index" || plan.Fields[0] != "Email" || plan.SObjectCardinality != 120000 {
+		t.Fatalf("unexpected plan %+v", plan)
+	}
+	if !result.Selective() || result.Plans[1].Selective() || result.Plans[1].Notes[0].Fields[0] != "IsActive" {
+		t.Fatalf("unexpected plans %+v", result.Plans)
+	}
+
+	// List view.
+	result, err = client.Explain(ctx, "00B000000000001AAA")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Selective() || result.SourceQuery != "SELECT Id FROM Account" {
+		t.Fatalf("unexpected result %+v", result)
+	}
+
+	// Negative: malformed query.
+	var sfErr SalesforceError
+	if _, err := client.Explain(ctx, "SELECT"); !errors.As(err, &sfErr) || sfErr.ErrorCode != "MALFORMED_QUERY" {
+		t.Fatalf("expected MALFORMED_QUERY, got %v", err)
+	}
+	if ok := (&ExplainResult{}).Selective(); ok {
+		t.Fatal("expected a result without plans not to be selective")
+	}
+}
@@ -0,0 +1,35 @@
+package simpleforce
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+)
+
+// hashedFileName returns the name of the file holding the value of key, with the extension ext. Keys are hashed as
+// they usually contain URLs and user names.
+func hashedFileName(key, ext string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:]) + ext
+}
+
+// writeFileAtomic writes data to the file name in dir. The file is replaced atomically so concurrent readers never see
+// partial data.
+func writeFileAtomic(dir, name string, data []byte) error {
+	tmp, err := os.CreateTemp(dir, ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), filepath.Join(dir, name))
+}
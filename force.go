@@ -3,15 +3,19 @@ package simpleforce
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
-	"encoding/xml"
+	"errors"
 	"fmt"
-	"html"
 	"io"
 	"net/http"
 	"net/url"
 	"os"
+	"sort"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
 	"go.uber.org/zap"
@@ -42,6 +46,30 @@ type Client struct {
 	instanceURL   string
 	useToolingAPI bool
 	httpClient    *uhttp.BaseHttpClient
+
+	// identityURL is the identity URL returned with an OAuth token.
+	identityURL string
+	// identity caches the result of CurrentUser.
+	identity *Identity
+	// issuedAt is the time the OAuth token was issued.
+	issuedAt time.Time
+	// refreshToken is the OAuth refresh token used by LoginRefreshToken.
+	refreshToken string
+	// renewToken re-runs the OAuth grant the client logged in with. It is nil if the session can't be renewed.
+	renewToken func(ctx context.Context) (*tokenResponse, error)
+	// tokenStore shares the session with other clients under tokenStoreKey. It is nil if no token store is used.
+	tokenStore    TokenStore
+	tokenStoreKey string
+	// restoredSession is set when the session was adopted from the token store and no login happened since.
+	restoredSession bool
+	// sessionMu guards the session: sessionID, user, instanceURL, identityURL, identity, issuedAt, refreshToken,
+	// renewToken and restoredSession, which renewals replace while other requests are in flight.
+	sessionMu sync.RWMutex
+	// renewMu serializes session renewals and logouts.
+	renewMu sync.Mutex
+	// describeCache caches describe results for describeCacheTTL. It is nil if no describe cache is used.
+	describeCache    DescribeCache
+	describeCacheTTL time.Duration
 }
 
 // QueryResult holds the response data from an SOQL query.
@@ -54,22 +82,37 @@ type QueryResult struct {
 
 // Expose sid to save in admin settings
 func (client *Client) GetSid() (sid string) {
-	return client.sessionID
+	return client.getSessionID()
 }
 
 // Expose Loc to save in admin settings
 func (client *Client) GetLoc() (loc string) {
-	return client.instanceURL
+	return client.getInstanceURL()
 }
 
-// Set SID and Loc as a means to log in without LoginPassword
+// Set SID and Loc as a means to log in without LoginPassword. The OAuth grant, tokens and identity of an earlier login
+// are dropped, so the session is never renewed or logged out as the previous user.
 func (client *Client) SetSidLoc(sid string, loc string) {
-	client.sessionID = sid
-	client.instanceURL = loc
+	client.renewMu.Lock()
+	defer client.renewMu.Unlock()
+	client.resetSession(sid, loc)
+}
+
+// Query runs an SOQL query. q could either be the SOQL string or the nextRecordsURL. opts customize the call, e.g.
+// WithBatchSize.
+func (client *Client) Query(ctx context.Context, q string, opts ...CallOption) (*QueryResult, error) {
+	return client.query(ctx, "query", q, opts...)
 }
 
-// Query runs an SOQL query. q could either be the SOQL string or the nextRecordsURL.
-func (client *Client) Query(ctx context.Context, q string) (*QueryResult, error) {
+// QueryAll runs an SOQL query like Query, but includes records which have been deleted (and are still in the recycle
+// bin) or archived, e.g. Tasks and Events. Select the IsDeleted field to tell them apart, see SObject.IsDeleted.
+// Ref: https://developer.salesforce.com/docs/atlas.en-us.api_rest.meta/api_rest/resources_queryall.htm
+func (client *Client) QueryAll(ctx context.Context, q string, opts ...CallOption) (*QueryResult, error) {
+	return client.query(ctx, "queryAll", q, opts...)
+}
+
+// query runs an SOQL query against resource, which is either "query" or "queryAll".
+func (client *Client) query(ctx context.Context, resource, q string, opts ...CallOption) (*QueryResult, error) {
 	if !client.isLoggedIn() {
 		return nil, ErrAuthentication
 	}
@@ -77,18 +120,18 @@ func (client *Client) Query(ctx context.Context, q string) (*QueryResult, error)
 	var u string
 	if strings.HasPrefix(q, "/services/data") {
 		// q is nextRecordsURL.
-		u = fmt.Sprintf("%s%s", client.instanceURL, q)
+		u = fmt.Sprintf("%s%s", client.getInstanceURL(), q)
 	} else {
 		// q is SOQL.
-		formatString := "%s/services/data/v%s/query?q=%s"
-		baseURL := client.instanceURL
+		formatString := "%s/services/data/v%s/%s?q=%s"
+		baseURL := client.getInstanceURL()
 		if client.useToolingAPI {
-			formatString = strings.Replace(formatString, "query", "tooling/query", -1)
+			resource = "tooling/" + resource
 		}
-		u = fmt.Sprintf(formatString, baseURL, client.apiVersion, url.QueryEscape(q))
+		u = fmt.Sprintf(formatString, baseURL, client.apiVersion, resource, url.QueryEscape(q))
 	}
 
-	data, err := client.httpRequest(ctx, http.MethodGet, u, nil)
+	data, err := client.httpRequest(ctx, http.MethodGet, u, nil, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -115,7 +158,7 @@ func (client *Client) ApexREST(ctx context.Context, method, path string, request
 		return nil, ErrAuthentication
 	}
 
-	u := fmt.Sprintf("%s/%s", client.instanceURL, path)
+	u := fmt.Sprintf("%s/%s", client.getInstanceURL(), path)
 
 	data, err := client.httpRequest(ctx, method, u, requestBody)
 	if err != nil {
@@ -138,7 +181,54 @@ func (client *Client) SObject(typeName ...string) *SObject {
 
 // isLoggedIn returns if the login to salesforce is successful.
 func (client *Client) isLoggedIn() bool {
-	return client.sessionID != ""
+	return client.getSessionID() != ""
+}
+
+// getSessionID returns the current session ID.
+func (client *Client) getSessionID() string {
+	client.sessionMu.RLock()
+	defer client.sessionMu.RUnlock()
+	return client.sessionID
+}
+
+// getSession returns the session ID and the instance URL of the current session.
+func (client *Client) getSession() (sessionID, instanceURL string) {
+	client.sessionMu.RLock()
+	defer client.sessionMu.RUnlock()
+	return client.sessionID, client.instanceURL
+}
+
+// getInstanceURL returns the instance URL of the current session.
+func (client *Client) getInstanceURL() string {
+	client.sessionMu.RLock()
+	defer client.sessionMu.RUnlock()
+	return client.instanceURL
+}
+
+// getUserName returns the name of the user the client is logged in as, if known.
+func (client *Client) getUserName() string {
+	client.sessionMu.RLock()
+	defer client.sessionMu.RUnlock()
+	return client.user.name
+}
+
+// resetSession replaces the session with the session ID sid on the instance loc, forgetting the OAuth grant, the
+// tokens and the identity of the previous session.
+func (client *Client) resetSession(sid, loc string) {
+	client.sessionMu.Lock()
+	defer client.sessionMu.Unlock()
+	client.sessionID = sid
+	client.instanceURL = loc
+	client.identityURL = ""
+	client.identity = nil
+	client.issuedAt = time.Time{}
+	client.refreshToken = ""
+	client.renewToken = nil
+	client.restoredSession = false
+	client.user.id = ""
+	client.user.name = ""
+	client.user.email = ""
+	client.user.fullName = ""
 }
 
 // LoginPassword signs into salesforce using password. token is optional if trusted IP is configured.
@@ -150,123 +240,226 @@ func (client *Client) LoginPassword(ctx context.Context, username, password, tok
 	// Use the SOAP interface to acquire session ID with username, password, and token.
 	// Do not use REST interface here as REST interface seems to have strong checking against client_id, while the SOAP
 	// interface allows a non-exist placeholder client_id to be used.
-	soapBody := `<?xml version="1.0" encoding="utf-8" ?>
-        <env:Envelope
-                xmlns:xsd="http://www.w3.org/2001/XMLSchema"
-                xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance"
-                xmlns:env="http://schemas.xmlsoap.org/soap/envelope/"
-                xmlns:urn="urn:partner.soap.sforce.com">
-            <env:Header>
-                <urn:CallOptions>
-                    <urn:client>%s</urn:client>
-                    <urn:defaultNamespace>sf</urn:defaultNamespace>
-                </urn:CallOptions>
-            </env:Header>
-            <env:Body>
-                <n1:login xmlns:n1="urn:partner.soap.sforce.com">
-                    <n1:username>%s</n1:username>
-                    <n1:password>%s%s</n1:password>
-                </n1:login>
-            </env:Body>
-        </env:Envelope>`
-	soapBody = fmt.Sprintf(soapBody, client.clientID, username, html.EscapeString(password), token)
-
-	requestUrl := fmt.Sprintf("%s/services/Soap/u/%s", client.baseURL, client.apiVersion)
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, requestUrl, strings.NewReader(soapBody))
+	envelope := client.newSOAPEnvelope("", soapLoginRequest{
+		Username: username,
+		Password: password + token,
+	})
+
+	l.Debug("Sending SOAP login request", zap.String("username", username))
+
+	var loginResponse soapLoginResponse
+	err := client.soapCall(ctx, client.baseURL, "login", envelope, &loginResponse)
 	if err != nil {
-		l.Error("error occurred creating request", zap.Error(err))
 		return err
 	}
-	req.Header.Add("Content-Type", "text/xml")
-	req.Header.Add("charset", "UTF-8")
-	req.Header.Add("SOAPAction", "login")
 
-	l.Debug("Sending SOAP login request",
-		zap.String("request_url", requestUrl),
-		zap.String("username", username),
-		zap.Any("request_headers", req.Header))
+	// Now we should all be good and the sessionID can be used to talk to salesforce further.
+	client.resetSession(loginResponse.SessionID, parseHost(loginResponse.ServerURL))
+	client.sessionMu.Lock()
+	client.issuedAt = time.Now()
+	client.user.id = loginResponse.UserID
+	client.user.name = loginResponse.UserName
+	client.user.email = loginResponse.UserEmail
+	client.user.fullName = loginResponse.UserFullName
+	client.sessionMu.Unlock()
+	client.saveToken(ctx)
+
+	l.Info("User authenticated", zap.String("user", loginResponse.UserName))
+	return nil
+}
 
-	resp, err := client.httpClient.Do(req)
-	// Handle non-API errors
-	if err != nil && resp == nil {
-		l.Error("error occurred submitting request", zap.Error(err))
-		return err
+// LogoutOption customizes Logout.
+type LogoutOption func(options *logoutOptions)
+
+type logoutOptions struct {
+	revokeRefreshToken bool
+}
+
+// WithRevokeRefreshToken makes Logout revoke the refresh token of an OAuth session instead of its access token. This
+// also revokes the authorization of the connected app, so the refresh token can't be used to log in again, neither by
+// this client nor by anyone else holding it.
+func WithRevokeRefreshToken() LogoutOption {
+	return func(options *logoutOptions) {
+		options.revokeRefreshToken = true
 	}
-	defer resp.Body.Close()
+}
 
-	// We have a response, but there's still possibly an error from the request, so we'll attempt to parse the error & body.
-	if resp.StatusCode != http.StatusOK {
-		l.Error("request failed", zap.Int("status_code", resp.StatusCode))
+// Logout ends the session: password sessions are logged out through the SOAP API and OAuth sessions have their access
+// token revoked. The refresh token LoginRefreshToken was called with stays valid, unless WithRevokeRefreshToken is
+// given. The client state is cleared even if salesforce rejects the call, and the session is removed from the token
+// store, so that subsequent calls return ErrAuthentication until the client logs in again. Logging out a client
+// without session does nothing.
+// Ref: https://developer.salesforce.com/docs/atlas.en-us.api.meta/api/sforce_api_calls_logout.htm
+// Ref: https://help.salesforce.com/s/articleView?id=sf.remoteaccess_revoke_token.htm
+func (client *Client) Logout(ctx context.Context, opts ...LogoutOption) error {
+	l := ctxzap.Extract(ctx)
 
-		buf := new(bytes.Buffer)
-		_, _ = buf.ReadFrom(resp.Body)
-		newStr := buf.String()
-		l.Error("Failed resp.body", zap.String("body", newStr))
-		theError := ParseSalesforceError(resp.StatusCode, buf.Bytes())
-		return theError
+	var options logoutOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	sessionID := client.getSessionID()
+	if sessionID == "" {
+		return nil
 	}
 
-	respData, err := io.ReadAll(resp.Body)
+	var err error
+	if client.isOAuthSession() {
+		token := sessionID
+		if refreshToken := client.getRefreshToken(); options.revokeRefreshToken && refreshToken != "" {
+			token = refreshToken
+		}
+		err = client.revokeToken(ctx, token)
+	} else {
+		envelope := client.newSOAPEnvelope(sessionID, soapLogoutRequest{})
+		err = client.soapCall(ctx, client.getInstanceURL(), "logout", envelope, nil)
+	}
 
+	// A session which already expired is as good as logged out.
+	var sfErr SalesforceError
+	if err != nil && (isInvalidSession(err) || errors.As(err, &sfErr) && sfErr.ErrorCode == "invalid_token") {
+		err = nil
+	}
 	if err != nil {
-		l.Error("error occurred reading response data", zap.Error(err))
+		l.Error("error occurred logging out", zap.Error(err))
 	}
 
-	var loginResponse struct {
-		XMLName      xml.Name `xml:"Envelope"`
-		ServerURL    string   `xml:"Body>loginResponse>result>serverUrl"`
-		SessionID    string   `xml:"Body>loginResponse>result>sessionId"`
-		UserID       string   `xml:"Body>loginResponse>result>userId"`
-		UserEmail    string   `xml:"Body>loginResponse>result>userInfo>userEmail"`
-		UserFullName string   `xml:"Body>loginResponse>result>userInfo>userFullName"`
-		UserName     string   `xml:"Body>loginResponse>result>userInfo>userName"`
+	client.renewMu.Lock()
+	client.resetSession("", "")
+	client.renewMu.Unlock()
+
+	if client.tokenStore != nil {
+		deleteErr := client.tokenStore.Delete(ctx, client.tokenStoreKey)
+		if deleteErr != nil {
+			l.Warn("failed to delete token", zap.Error(deleteErr))
+		}
 	}
 
-	err = xml.Unmarshal(respData, &loginResponse)
+	l.Info("User logged out")
+	return err
+}
+
+// httpRequest executes an HTTP request to the salesforce server and returns the response data in byte buffer.
+// If the session has expired and the client is able to renew it, the session is renewed and the request is retried
+// once. opts set additional request headers.
+func (client *Client) httpRequest(ctx context.Context, method, url string, body io.Reader, opts ...CallOption) ([]byte, error) {
+	data, _, err := client.httpRequestResponse(ctx, method, url, body, opts...)
+	return data, err
+}
+
+// httpRequestResponse executes an HTTP request like httpRequest, also returning the response, whose body has been
+// read into data already. A 304 Not Modified response is returned without error, with nil data, if the request is
+// conditional, i.e. opts set If-Modified-Since; otherwise it is an error like any other unexpected status.
+func (client *Client) httpRequestResponse(ctx context.Context, method, url string, body io.Reader, opts ...CallOption) ([]byte, *http.Response, error) {
+	if !client.isLoggedIn() {
+		return nil, nil, ErrAuthentication
+	}
+
+	// Buffer the body so that it can be sent again after renewing the session.
+	var payload []byte
+	if body != nil {
+		var err error
+		payload, err = io.ReadAll(body)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	sessionID, instanceURL := client.getSession()
+	data, resp, err := client.doHttpRequest(ctx, method, url, payload, sessionID, opts)
+	if err == nil || client.getRenewToken() == nil || !isInvalidSession(err) {
+		return data, resp, err
+	}
+
+	err = client.renewSession(ctx, sessionID)
 	if err != nil {
-		l.Error("error occurred parsing login response", zap.Error(err))
-		return err
+		return nil, nil, err
 	}
 
-	// Now we should all be good and the sessionID can be used to talk to salesforce further.
-	client.sessionID = loginResponse.SessionID
-	client.instanceURL = parseHost(loginResponse.ServerURL)
-	client.user.id = loginResponse.UserID
-	client.user.name = loginResponse.UserName
-	client.user.email = loginResponse.UserEmail
-	client.user.fullName = loginResponse.UserFullName
+	// The renewed session may live on another instance, so requests to the old instance are sent to the new one.
+	sessionID, renewedInstanceURL := client.getSession()
+	if path, ok := strings.CutPrefix(url, instanceURL); ok && instanceURL != "" && (path == "" || path[0] == '/') {
+		url = renewedInstanceURL + path
+	}
 
-	l.Info("User authenticated", zap.String("user", client.user.name))
-	return nil
+	return client.doHttpRequest(ctx, method, url, payload, sessionID, opts)
 }
 
-// httpRequest executes an HTTP request to the salesforce server and returns the response data in byte buffer.
-func (client *Client) httpRequest(ctx context.Context, method, url string, body io.Reader) ([]byte, error) {
+// doHttpRequest executes a single HTTP request authorized with sessionID.
+func (client *Client) doHttpRequest(ctx context.Context, method, url string, payload []byte, sessionID string, opts []CallOption) ([]byte, *http.Response, error) {
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+
 	req, err := http.NewRequestWithContext(ctx, method, url, body)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", client.sessionID))
+	req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", sessionID))
 	req.Header.Add("Content-Type", "application/json")
+	for _, opt := range opts {
+		opt(req.Header)
+	}
+	conditional := req.Header.Get("If-Modified-Since") != ""
+
+	if method == http.MethodGet && len(opts) > 0 {
+		addCacheKey(req)
+	}
 
 	resp, err := client.httpClient.Do(req)
+	if resp != nil && resp.StatusCode == http.StatusNotModified && conditional {
+		resp.Body.Close()
+		return nil, resp, nil
+	}
 	if err != nil {
-		return nil, parseUhttpError(ctx, resp, err)
+		return nil, nil, parseUhttpError(ctx, resp, err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode < 200 || resp.StatusCode > 299 {
-		return nil, parseUhttpError(ctx, resp, err)
+		return nil, nil, parseUhttpError(ctx, resp, err)
 	}
 
-	return io.ReadAll(resp.Body)
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+	return data, resp, nil
+}
+
+// cacheKeyParam is the query parameter which tells GET requests with call options apart in the response cache of
+// uhttp. Its cache key ignores most request headers, so a request with e.g. a batch size or If-Modified-Since would
+// otherwise be answered with the cached response of another request to the same URL, without reaching salesforce.
+const cacheKeyParam = "_headers"
+
+// addCacheKey adds a hash of the request headers, except for the session, to the query of req.
+func addCacheKey(req *http.Request) {
+	names := make([]string, 0, len(req.Header))
+	for name := range req.Header {
+		if name != "Authorization" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	hash := sha256.New()
+	for _, name := range names {
+		fmt.Fprintf(hash, "%s: %s\n", name, strings.Join(req.Header.Values(name), ", "))
+	}
+	param := cacheKeyParam + "=" + hex.EncodeToString(hash.Sum(nil))[:16]
+	if req.URL.RawQuery == "" {
+		req.URL.RawQuery = param
+	} else {
+		req.URL.RawQuery += "&" + param
+	}
 }
 
 // makeURL generates a REST API URL based on baseURL, APIVersion of the client.
 func (client *Client) makeURL(req string) string {
-	client.apiVersion = strings.Replace(client.apiVersion, "v", "", -1)
-	retURL := fmt.Sprintf("%s/services/data/v%s/%s", client.instanceURL, client.apiVersion, req)
+	retURL := fmt.Sprintf("%s/services/data/v%s/%s", client.getInstanceURL(), client.apiVersion, req)
 	return retURL
 }
 
@@ -284,7 +477,7 @@ func NewClient(ctx context.Context, url, clientID, apiVersion string) (*Client,
 	}
 
 	client := &Client{
-		apiVersion: apiVersion,
+		apiVersion: strings.Replace(apiVersion, "v", "", -1),
 		baseURL:    url,
 		clientID:   clientID,
 		httpClient: uhttpClient,
@@ -316,14 +509,14 @@ func (client *Client) DownloadAttachment(attachmentId string, filepath string) e
 func (client *Client) download(apiPath string, filepath string) error {
 	// Get the data
 	httpClient := client.httpClient
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s%s", strings.TrimRight(client.instanceURL, "/"), apiPath), nil)
+	req, err := http.NewRequest("GET", fmt.Sprintf("%s%s", strings.TrimRight(client.getInstanceURL(), "/"), apiPath), nil)
 	if err != nil {
 		return err
 	}
 
 	req.Header.Add("Content-Type", "application/json; charset=UTF-8")
 	req.Header.Add("Accept", "application/json")
-	req.Header.Add("Authorization", "Bearer "+client.sessionID)
+	req.Header.Add("Authorization", "Bearer "+client.getSessionID())
 
 	resp, err := httpClient.Do(req)
 	if err != nil {
@@ -357,39 +550,17 @@ func parseHost(input string) string {
 	return "Failed to parse URL input"
 }
 
-// Get the List of all available objects and their metadata for your organization's data
+// DescribeGlobal lists all available objects and their metadata for your organization's data. Use
+// DescribeGlobalTyped for a typed result.
+// Ref: https://developer.salesforce.com/docs/atlas.en-us.api_rest.meta/api_rest/resources_describeGlobal.htm
 func (client *Client) DescribeGlobal(ctx context.Context) (*SObjectMeta, error) {
-	l := ctxzap.Extract(ctx)
-
-	apiPath := fmt.Sprintf("/services/data/v%s/sobjects", client.apiVersion)
-	baseURL := strings.TrimRight(client.baseURL, "/")
-	url := fmt.Sprintf("%s%s", baseURL, apiPath) // Get the objects
-	httpClient := client.httpClient
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	data, err := client.describeGlobal(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	req.Header.Add("Content-Type", "application/json; charset=UTF-8")
-	req.Header.Add("Accept", "application/json")
-	req.Header.Add("Authorization", "Bearer "+client.sessionID)
-	// resp, err := http.Get(url)
-	resp, err := httpClient.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
 	var meta SObjectMeta
-	l.Info("status code", zap.Int("status_code", resp.StatusCode), zap.String("url", url))
-
-	respData, err := io.ReadAll(resp.Body)
-	if err != nil {
-		l.Error("Failed to read response body", zap.Error(err))
-	}
-
-	err = json.Unmarshal(respData, &meta)
+	err = json.Unmarshal(data, &meta)
 	if err != nil {
 		return nil, err
 	}
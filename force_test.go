@@ -2,11 +2,16 @@ package simpleforce
 
 import (
 	"context"
+	"encoding/xml"
+	"errors"
+	"io"
 	"log"
 	"net/http"
+	"net/http/httptest"
 	"os"
 	"strings"
 	"testing"
+	"time"
 )
 
 var (
@@ -53,6 +58,31 @@ func requireClient(ctx context.Context, t *testing.T, skippable bool) *Client {
 	return client
 }
 
+// newTestClient returns a client whose login URL points to an httptest server serving handler. The uhttp response
+// cache is disabled so repeated requests reach the handler.
+func newTestClient(t *testing.T, handler http.Handler) *Client {
+	t.Helper()
+	t.Setenv("BATON_DISABLE_HTTP_CACHE", "true")
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	client, err := NewClient(context.Background(), server.URL, DefaultClientID, DefaultAPIVersion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+// newLoggedInTestClient returns a test client with a session on the httptest server.
+func newLoggedInTestClient(t *testing.T, handler http.Handler) *Client {
+	t.Helper()
+
+	client := newTestClient(t, handler)
+	client.SetSidLoc("__SESSION_ID__", client.baseURL)
+	return client
+}
+
 func TestClient_LoginPassword(t *testing.T) {
 	ctx := context.Background()
 
@@ -226,3 +256,164 @@ func TestClient_QueryLike(t *testing.T) {
 func TestMain(m *testing.M) {
 	m.Run()
 }
+
+func TestClient_Logout(t *testing.T) {
+	ctx := context.Background()
+
+	var soapLogouts, revokedTokens []string
+	mux := newTestOAuthServer(t, func(r *http.Request) string {
+		return "__ACCESS_TOKEN__"
+	})
+	mux.HandleFunc("/services/Soap/u/"+DefaultAPIVersion, func(w http.ResponseWriter, r *http.Request) {
+		var logout struct {
+			SessionID string   `xml:"Header>SessionHeader>sessionId"`
+			Logout    xml.Name `xml:"Body>logout"`
+		}
+		body, _ := io.ReadAll(r.Body)
+		if err := xml.Unmarshal(body, &logout); err != nil || logout.Logout.Local != "logout" {
+			t.Errorf("unexpected SOAP request %s", body)
+		}
+		soapLogouts = append(soapLogouts, logout.SessionID)
+		w.Header().Set("Content-Type", "text/xml")
+		_, _ = w.Write([]byte(`<soapenv:Envelope xmlns:soapenv="http://schemas.xmlsoap.org/soap/envelope/"><soapenv:Body><logoutResponse/></soapenv:Body></soapenv:Envelope>`))
+	})
+	mux.HandleFunc("/services/oauth2/revoke", func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Error(err)
+		}
+		revokedTokens = append(revokedTokens, r.PostForm.Get("token"))
+	})
+
+	store := NewMemoryTokenStore()
+	client := newTestClient(t, mux)
+	if _, err := client.UseTokenStore(ctx, store, "org"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Password session.
+	client.SetSidLoc("__SESSION_ID__", client.baseURL)
+	if err := client.Logout(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if len(soapLogouts) != 1 || soapLogouts[0] != "__SESSION_ID__" || len(revokedTokens) != 0 {
+		t.Fatalf("expected a SOAP logout, got %v and revoked %v", soapLogouts, revokedTokens)
+	}
+
+	// OAuth session.
+	if err := client.LoginClientCredentials(ctx, "__CONSUMER_KEY__", "__CONSUMER_SECRET__"); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Logout(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if len(soapLogouts) != 1 || len(revokedTokens) != 1 || revokedTokens[0] != "__ACCESS_TOKEN__" {
+		t.Fatalf("expected a token revocation, got %v and revoked %v", soapLogouts, revokedTokens)
+	}
+
+	if client.GetSid() != "" || client.user.id != "" {
+		t.Fatalf("client state was not cleared")
+	}
+	if tok, err := store.Load(ctx, "org"); err != nil || tok != nil {
+		t.Fatalf("expected stored token to be deleted, got %v, %v", tok, err)
+	}
+	if _, err := client.Query(ctx, "SELECT Id FROM User"); !errors.Is(err, ErrAuthentication) {
+		t.Fatalf("expected ErrAuthentication, got %v", err)
+	}
+	if _, err := client.SObject("User").Get(ctx, testUserID); !errors.Is(err, ErrAuthentication) {
+		t.Fatalf("expected ErrAuthentication, got %v", err)
+	}
+
+	// Logging out twice does nothing.
+	if err := client.Logout(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if len(soapLogouts) != 1 || len(revokedTokens) != 1 {
+		t.Fatalf("unexpected calls after logout")
+	}
+}
+
+func TestClient_LogoutRefreshToken(t *testing.T) {
+	ctx := context.Background()
+
+	var revokedTokens []string
+	mux := newTestOAuthServer(t, func(r *http.Request) string {
+		if r.PostForm.Get("refresh_token") != "__REFRESH_TOKEN__" {
+			return ""
+		}
+		return "__ACCESS_TOKEN__"
+	})
+	mux.HandleFunc("/services/oauth2/revoke", func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Error(err)
+		}
+		revokedTokens = append(revokedTokens, r.PostForm.Get("token"))
+	})
+	client := newTestClient(t, mux)
+
+	// By default only the access token is revoked, so the refresh token can log in again.
+	if err := client.LoginRefreshToken(ctx, "__CONSUMER_KEY__", "", "__REFRESH_TOKEN__"); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Logout(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if len(revokedTokens) != 1 || revokedTokens[0] != "__ACCESS_TOKEN__" {
+		t.Fatalf("expected the access token to be revoked, got %v", revokedTokens)
+	}
+	if client.GetSid() != "" || client.getRefreshToken() != "" {
+		t.Fatalf("client state was not cleared")
+	}
+
+	// The refresh token is only revoked on request.
+	if err := client.LoginRefreshToken(ctx, "__CONSUMER_KEY__", "", "__REFRESH_TOKEN__"); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Logout(ctx, WithRevokeRefreshToken()); err != nil {
+		t.Fatal(err)
+	}
+	if len(revokedTokens) != 2 || revokedTokens[1] != "__REFRESH_TOKEN__" {
+		t.Fatalf("expected the refresh token to be revoked, got %v", revokedTokens)
+	}
+}
+
+func TestClient_HttpCacheBypass(t *testing.T) {
+	ctx := context.Background()
+
+	// Keep the default response cache of uhttp, unlike newTestClient.
+	t.Setenv("BATON_DISABLE_HTTP_CACHE", "false")
+	describeServer := &testDescribeCacheServer{lastModified: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), label: "User"}
+	queryServer := newTestQueryServer(t, 5, 2)
+	mux := http.NewServeMux()
+	mux.Handle("/services/data/v"+DefaultAPIVersion+"/sobjects/", describeServer)
+	mux.Handle("/services/data/v"+DefaultAPIVersion+"/query", queryServer)
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	client, err := NewClient(ctx, server.URL, DefaultClientID, DefaultAPIVersion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.SetSidLoc("__SESSION_ID__", server.URL)
+
+	// Revalidations of the describe cache reach salesforce.
+	client.UseDescribeCache(NewMemoryDescribeCache(), 0)
+	for i := 0; i < 3; i++ {
+		if _, err := client.DescribeSObject(ctx, "User"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if requests, notModified := describeServer.counts(); requests != 3 || notModified != 2 {
+		t.Fatalf("expected conditional requests, got %d requests, %d not modified", requests, notModified)
+	}
+
+	// Queries with call options aren't answered from the cache of another batch size.
+	for _, size := range []int{2, 3} {
+		result, err := client.Query(ctx, "SELECT Id FROM User", WithBatchSize(size))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(result.Records) != size {
+			t.Fatalf("expected a batch of %d records, got %d", size, len(result.Records))
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package simpleforce
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// FrontdoorURL returns a URL which opens a browser session in the org without entering credentials. retURL is the
+// path relative to the instance to land on, e.g. "/lightning/setup/SetupOneHome/home"; the home page is used if it is
+// empty. OAuth sessions are exchanged for a single-access URL, which can only be used once; for other sessions the
+// session ID is passed to frontdoor.jsp directly, so the URL must be treated like the session itself.
+// Ref: https://help.salesforce.com/s/articleView?id=sf.frontdoor_singleaccess.htm
+func (client *Client) FrontdoorURL(ctx context.Context, retURL string) (string, error) {
+	sessionID := client.getSessionID()
+	if sessionID == "" {
+		return "", ErrAuthentication
+	}
+
+	if client.isOAuthSession() {
+		return client.singleAccessURL(ctx, retURL)
+	}
+
+	params := url.Values{}
+	params.Set("sid", sessionID)
+	if retURL != "" {
+		params.Set("retURL", retURL)
+	}
+	return fmt.Sprintf("%s/secur/frontdoor.jsp?%s", client.getInstanceURL(), params.Encode()), nil
+}
+
+// singleAccessURL exchanges the OAuth session for a single-access frontdoor URL, renewing the session if it has
+// expired.
+func (client *Client) singleAccessURL(ctx context.Context, retURL string) (string, error) {
+	l := ctxzap.Extract(ctx)
+
+	form := url.Values{}
+	if retURL != "" {
+		form.Set("redirect_uri", retURL)
+	}
+
+	requestUrl := fmt.Sprintf("%s/services/oauth2/singleaccess", client.getInstanceURL())
+	respData, err := client.httpRequest(ctx, http.MethodPost, requestUrl, strings.NewReader(form.Encode()),
+		WithHeader("Content-Type", "application/x-www-form-urlencoded"), WithHeader("Accept", "application/json"))
+	if err != nil {
+		l.Error("error occurred requesting single-access URL", zap.Error(err))
+		return "", err
+	}
+
+	var result struct {
+		FrontdoorURI string `json:"frontdoor_uri"`
+	}
+	err = json.Unmarshal(respData, &result)
+	if err != nil {
+		return "", err
+	}
+	if result.FrontdoorURI == "" {
+		return "", errors.New("no frontdoor uri returned")
+	}
+	return result.FrontdoorURI, nil
+}
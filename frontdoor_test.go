@@ -0,0 +1,87 @@
+package simpleforce
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"testing"
+)
+
+func TestClient_FrontdoorURL(t *testing.T) {
+	ctx := context.Background()
+
+	var (
+		mu         sync.Mutex
+		issued     int
+		validToken string
+	)
+	mux := newTestOAuthServer(t, func(r *http.Request) string {
+		mu.Lock()
+		defer mu.Unlock()
+		issued++
+		validToken = fmt.Sprintf("__ACCESS_TOKEN_%d__", issued)
+		return validToken
+	})
+	mux.HandleFunc("/services/oauth2/singleaccess", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Type") != "application/x-www-form-urlencoded" {
+			t.Errorf("unexpected content type %q", r.Header.Get("Content-Type"))
+		}
+		if err := r.ParseForm(); err != nil {
+			t.Error(err)
+		}
+		mu.Lock()
+		valid := r.Header.Get("Authorization") == "Bearer "+validToken
+		mu.Unlock()
+		if r.Method != http.MethodPost || !valid {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		frontdoor := "http://" + r.Host + "/secur/frontdoor.jsp?otp=__OTP__&startURL=" + url.QueryEscape(r.PostForm.Get("redirect_uri"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"frontdoor_uri": "` + frontdoor + `"}`))
+	})
+
+	client := newTestClient(t, mux)
+
+	// Negative: not logged in.
+	if _, err := client.FrontdoorURL(ctx, ""); !errors.Is(err, ErrAuthentication) {
+		t.Fatalf("expected ErrAuthentication, got %v", err)
+	}
+
+	// Session ID.
+	client.SetSidLoc("__SESSION_ID__", client.baseURL)
+	u, err := client.FrontdoorURL(ctx, "/lightning/page/home")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u != client.baseURL+"/secur/frontdoor.jsp?retURL=%2Flightning%2Fpage%2Fhome&sid=__SESSION_ID__" {
+		t.Fatalf("unexpected URL %q", u)
+	}
+
+	// OAuth session.
+	if err := client.LoginClientCredentials(ctx, "__CONSUMER_KEY__", "__CONSUMER_SECRET__"); err != nil {
+		t.Fatal(err)
+	}
+	u, err = client.FrontdoorURL(ctx, "/lightning/page/home")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u != client.baseURL+"/secur/frontdoor.jsp?otp=__OTP__&startURL=%2Flightning%2Fpage%2Fhome" {
+		t.Fatalf("unexpected URL %q", u)
+	}
+
+	// Expired OAuth session.
+	mu.Lock()
+	validToken = "__EXPIRED__"
+	mu.Unlock()
+	u, err = client.FrontdoorURL(ctx, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u != client.baseURL+"/secur/frontdoor.jsp?otp=__OTP__&startURL=" || client.GetSid() != "__ACCESS_TOKEN_2__" {
+		t.Fatalf("unexpected URL %q for session %q", u, client.GetSid())
+	}
+}
@@ -0,0 +1,157 @@
+package simpleforce
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// idChecksumAlphabet holds the characters of the checksum of 18 character IDs, indexed by the case bits of a block.
+const idChecksumAlphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZ012345"
+
+// ID18 returns the case-insensitive 18 character form of the salesforce ID id, which may have 15 or 18 characters.
+// The checksum of an 18 character ID is validated; the case of its first 15 characters is restored from the
+// checksum, so IDs mangled by case-insensitive systems are accepted. ErrInvalidID is returned for anything else.
+func ID18(id string) (string, error) {
+	id15, err := ID15(id)
+	if err != nil {
+		return "", err
+	}
+	return id15 + idChecksum(id15), nil
+}
+
+// ID15 returns the case-sensitive 15 character form of the salesforce ID id, which may have 15 or 18 characters. The
+// checksum of an 18 character ID is validated, as done by ID18.
+func ID15(id string) (string, error) {
+	switch len(id) {
+	case 15:
+		for i := 0; i < len(id); i++ {
+			if strings.IndexByte(idAlphabet, id[i]) < 0 {
+				return "", fmt.Errorf("%w %q", ErrInvalidID, id)
+			}
+		}
+		return id, nil
+	case 18:
+	default:
+		return "", fmt.Errorf("%w %q", ErrInvalidID, id)
+	}
+
+	// Each checksum character holds the case of 5 characters, the lowest bit being the first character.
+	id15 := []byte(id[:15])
+	for block := 0; block < 3; block++ {
+		bits := strings.IndexByte(idChecksumAlphabet, upper(id[15+block]))
+		if bits < 0 {
+			return "", fmt.Errorf("%w %q: bad checksum", ErrInvalidID, id)
+		}
+		for i := 0; i < 5; i++ {
+			c := &id15[block*5+i]
+			isUpper := bits&(1<<i) != 0
+			switch {
+			case isLetter(*c) && isUpper:
+				*c = upper(*c)
+			case isLetter(*c):
+				*c = lower(*c)
+			case *c >= '0' && *c <= '9' && !isUpper:
+			default:
+				return "", fmt.Errorf("%w %q: bad checksum", ErrInvalidID, id)
+			}
+		}
+	}
+	return string(id15), nil
+}
+
+// KeyPrefixResolver resolves the SObject type of IDs from their key prefix, the first 3 characters. It is safe for
+// concurrent use.
+//
+//	resolver, err := client.NewKeyPrefixResolver(ctx)
+//	obj, err := resolver.SObject("005000000000001AAA")
+//	obj, err = obj.Get(ctx)
+type KeyPrefixResolver struct {
+	client *Client
+	types  map[string]string
+}
+
+// NewKeyPrefixResolver creates a KeyPrefixResolver knowing the key prefixes of the SObject types of the org, taken
+// from the global describe. Use a describe cache to avoid fetching it for every resolver.
+func (client *Client) NewKeyPrefixResolver(ctx context.Context) (*KeyPrefixResolver, error) {
+	result, err := client.DescribeGlobalTyped(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resolver := &KeyPrefixResolver{client: client, types: make(map[string]string)}
+	for _, sobject := range result.SObjects {
+		// Some types, e.g. feeds and shares, don't have a key prefix.
+		if sobject.KeyPrefix != "" {
+			resolver.types[sobject.KeyPrefix] = sobject.Name
+		}
+	}
+	return resolver, nil
+}
+
+// TypeName returns the name of the SObject type of the 15 or 18 character ID id. ErrInvalidID is returned if id isn't
+// a valid ID, and ErrUnknownKeyPrefix if no type of the org has its key prefix.
+func (resolver *KeyPrefixResolver) TypeName(id string) (string, error) {
+	id15, err := ID15(id)
+	if err != nil {
+		return "", err
+	}
+
+	prefix := id15[:3]
+	typeName, ok := resolver.types[prefix]
+	if !ok {
+		return "", fmt.Errorf("%w %q", ErrUnknownKeyPrefix, prefix)
+	}
+	return typeName, nil
+}
+
+// SObject returns an SObject of the type of id with its ID set to the 18 character form of id, ready to be retrieved
+// with Get.
+func (resolver *KeyPrefixResolver) SObject(id string) (*SObject, error) {
+	typeName, err := resolver.TypeName(id)
+	if err != nil {
+		return nil, err
+	}
+	id18, err := ID18(id)
+	if err != nil {
+		return nil, err
+	}
+
+	obj := resolver.client.SObject(typeName)
+	obj.setID(id18)
+	return obj, nil
+}
+
+// idChecksum returns the 3 character checksum of the 15 character ID id15.
+func idChecksum(id15 string) string {
+	checksum := make([]byte, 3)
+	for block := range checksum {
+		bits := 0
+		for i := 0; i < 5; i++ {
+			c := id15[block*5+i]
+			if c >= 'A' && c <= 'Z' {
+				bits |= 1 << i
+			}
+		}
+		checksum[block] = idChecksumAlphabet[bits]
+	}
+	return string(checksum)
+}
+
+func isLetter(c byte) bool {
+	return (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z')
+}
+
+func upper(c byte) byte {
+	if c >= 'a' && c <= 'z' {
+		return c - 'a' + 'A'
+	}
+	return c
+}
+
+func lower(c byte) byte {
+	if c >= 'A' && c <= 'Z' {
+		return c - 'A' + 'a'
+	}
+	return c
+}
@@ -0,0 +1,72 @@
+package simpleforce
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestID18(t *testing.T) {
+	tests := []struct {
+		id   string
+		id15 string
+		id18 string
+	}{
+		{"001A0000006Vm9r", "001A0000006Vm9r", "001A0000006Vm9rIAC"},
+		{"001A0000006Vm9rIAC", "001A0000006Vm9r", "001A0000006Vm9rIAC"},
+		{"003000000000000", "003000000000000", "003000000000000AAA"},
+		// The case is restored from the checksum.
+		{"001a0000006vm9riac", "001A0000006Vm9r", "001A0000006Vm9rIAC"},
+		{"005ABCDEFGHIJKL", "005ABCDEFGHIJKL", "005ABCDEFGHIJKLY55"},
+	}
+	for _, tt := range tests {
+		id15, err := ID15(tt.id)
+		if err != nil || id15 != tt.id15 {
+			t.Errorf("ID15(%q) = %q, %v, expected %q", tt.id, id15, err, tt.id15)
+		}
+		id18, err := ID18(tt.id)
+		if err != nil || id18 != tt.id18 {
+			t.Errorf("ID18(%q) = %q, %v, expected %q", tt.id, id18, err, tt.id18)
+		}
+	}
+
+	// Negative: bad lengths, characters and checksums.
+	for _, id := range []string{"", "001A0000006Vm9", "001A0000006Vm9rIA", "001A0000006Vm9-", "001A0000006Vm9rIAD", "001A0000006Vm9rIA6"} {
+		if _, err := ID18(id); !errors.Is(err, ErrInvalidID) {
+			t.Errorf("ID18(%q): expected ErrInvalidID, got %v", id, err)
+		}
+	}
+}
+
+func TestKeyPrefixResolver(t *testing.T) {
+	ctx := context.Background()
+
+	client := newLoggedInTestClient(t, newTestDescribeServer(t))
+	resolver, err := client.NewKeyPrefixResolver(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if typeName, err := resolver.TypeName("001A0000006Vm9r"); err != nil || typeName != "Account" {
+		t.Fatalf("unexpected type %q, %v", typeName, err)
+	}
+	obj, err := resolver.SObject("a00a0000006vm9riac")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obj.Type() != "Invoice__c" || obj.ID() != "a00A0000006Vm9rIAC" || obj.client() != client {
+		t.Fatalf("unexpected sobject %v", obj)
+	}
+
+	// Negative: unknown prefixes and invalid IDs.
+	if _, err := resolver.SObject("00Q000000000001"); !errors.Is(err, ErrUnknownKeyPrefix) {
+		t.Fatalf("expected ErrUnknownKeyPrefix, got %v", err)
+	}
+	if _, err := resolver.TypeName("001"); !errors.Is(err, ErrInvalidID) {
+		t.Fatalf("expected ErrInvalidID, got %v", err)
+	}
+	client.SetSidLoc("", client.instanceURL)
+	if _, err := client.NewKeyPrefixResolver(ctx); !errors.Is(err, ErrAuthentication) {
+		t.Fatalf("expected ErrAuthentication, got %v", err)
+	}
+}
@@ -0,0 +1,174 @@
+package simpleforce
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// Identity describes the user the client is logged in as.
+// Ref: https://help.salesforce.com/s/articleView?id=sf.remoteaccess_using_openid.htm
+type Identity struct {
+	ID             string            `json:"id"` // The identity URL.
+	UserID         string            `json:"user_id"`
+	OrganizationID string            `json:"organization_id"`
+	Username       string            `json:"username"`
+	DisplayName    string            `json:"display_name"`
+	NickName       string            `json:"nick_name"`
+	FirstName      string            `json:"first_name"`
+	LastName       string            `json:"last_name"`
+	Email          string            `json:"email"`
+	EmailVerified  bool              `json:"email_verified"`
+	Locale         string            `json:"locale"`
+	Language       string            `json:"language"`
+	Timezone       string            `json:"timezone"`
+	UTCOffset      int               `json:"utcOffset"`
+	UserType       string            `json:"user_type"`
+	Active         bool              `json:"active"`
+	URLs           map[string]string `json:"urls"`
+}
+
+// userInfo holds the response data of the OpenID Connect userinfo endpoint, which names most fields differently from
+// the identity URL.
+type userInfo struct {
+	Sub               string            `json:"sub"`
+	UserID            string            `json:"user_id"`
+	OrganizationID    string            `json:"organization_id"`
+	PreferredUsername string            `json:"preferred_username"`
+	Name              string            `json:"name"`
+	Nickname          string            `json:"nickname"`
+	GivenName         string            `json:"given_name"`
+	FamilyName        string            `json:"family_name"`
+	Email             string            `json:"email"`
+	EmailVerified     bool              `json:"email_verified"`
+	Locale            string            `json:"locale"`
+	Language          string            `json:"language"`
+	ZoneInfo          string            `json:"zoneinfo"`
+	UTCOffset         int               `json:"utcOffset"`
+	UserType          string            `json:"user_type"`
+	Active            bool              `json:"active"`
+	URLs              map[string]string `json:"urls"`
+}
+
+// CurrentUser returns the identity of the user the client is logged in as. The identity URL returned by OAuth logins
+// is used if known; otherwise, e.g. for SetSidLoc or password sessions, the userinfo endpoint of the instance is
+// called. The identity is cached on the client until it logs in again.
+func (client *Client) CurrentUser(ctx context.Context) (*Identity, error) {
+	if !client.isLoggedIn() {
+		return nil, ErrAuthentication
+	}
+
+	if identity := client.cachedIdentity(); identity != nil {
+		return identity, nil
+	}
+
+	var (
+		identity *Identity
+		err      error
+	)
+	if identityURL := client.getIdentityURL(); identityURL != "" {
+		identity, err = client.fetchIdentity(ctx, identityURL)
+	} else {
+		identity, err = client.fetchUserInfo(ctx)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	client.setIdentity(identity)
+	return client.cachedIdentity(), nil
+}
+
+// loadIdentity populates the user from the identity URL of the session, if there is one.
+func (client *Client) loadIdentity(ctx context.Context) error {
+	identityURL := client.getIdentityURL()
+	if identityURL == "" {
+		return nil
+	}
+
+	identity, err := client.fetchIdentity(ctx, identityURL)
+	if err != nil {
+		return err
+	}
+
+	client.setIdentity(identity)
+	return nil
+}
+
+// fetchIdentity calls identityURL, the identity URL of the session.
+func (client *Client) fetchIdentity(ctx context.Context, identityURL string) (*Identity, error) {
+	data, err := client.httpRequest(ctx, http.MethodGet, identityURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var identity Identity
+	err = json.Unmarshal(data, &identity)
+	if err != nil {
+		return nil, err
+	}
+	return &identity, nil
+}
+
+// fetchUserInfo calls the userinfo endpoint of the instance.
+func (client *Client) fetchUserInfo(ctx context.Context) (*Identity, error) {
+	u := fmt.Sprintf("%s/services/oauth2/userinfo", client.getInstanceURL())
+	data, err := client.httpRequest(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var info userInfo
+	err = json.Unmarshal(data, &info)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Identity{
+		ID:             info.Sub,
+		UserID:         info.UserID,
+		OrganizationID: info.OrganizationID,
+		Username:       info.PreferredUsername,
+		DisplayName:    info.Name,
+		NickName:       info.Nickname,
+		FirstName:      info.GivenName,
+		LastName:       info.FamilyName,
+		Email:          info.Email,
+		EmailVerified:  info.EmailVerified,
+		Locale:         info.Locale,
+		Language:       info.Language,
+		Timezone:       info.ZoneInfo,
+		UTCOffset:      info.UTCOffset,
+		UserType:       info.UserType,
+		Active:         info.Active,
+		URLs:           info.URLs,
+	}, nil
+}
+
+// cachedIdentity returns a copy of the cached identity, or nil if there is none.
+func (client *Client) cachedIdentity() *Identity {
+	client.sessionMu.RLock()
+	defer client.sessionMu.RUnlock()
+
+	if client.identity == nil {
+		return nil
+	}
+	identity := *client.identity
+	return &identity
+}
+
+// setIdentity caches identity and populates the user from it. A nil identity clears the cache.
+func (client *Client) setIdentity(identity *Identity) {
+	client.sessionMu.Lock()
+	defer client.sessionMu.Unlock()
+
+	client.identity = identity
+	if identity == nil {
+		return
+	}
+	client.user.id = identity.UserID
+	client.user.name = identity.Username
+	client.user.email = identity.Email
+	client.user.fullName = identity.DisplayName
+}
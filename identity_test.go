@@ -0,0 +1,89 @@
+package simpleforce
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestClient_CurrentUser(t *testing.T) {
+	ctx := context.Background()
+
+	mux := newTestOAuthServer(t, func(r *http.Request) string {
+		return "__ACCESS_TOKEN__"
+	})
+
+	client := newTestClient(t, mux)
+	if err := client.LoginClientCredentials(ctx, "__CONSUMER_KEY__", "__CONSUMER_SECRET__"); err != nil {
+		t.Fatal(err)
+	}
+
+	identity, err := client.CurrentUser(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if identity.UserID != testUserID || identity.OrganizationID != testOrgID || identity.Username != testUsername ||
+		identity.Locale != "en_US" || identity.Timezone != "America/Los_Angeles" || !identity.Active ||
+		identity.URLs["rest"] != client.baseURL+"/services/data/v{version}/" {
+		t.Fatalf("unexpected identity %+v", identity)
+	}
+}
+
+func TestClient_CurrentUserInfo(t *testing.T) {
+	ctx := context.Background()
+
+	calls := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("/services/oauth2/userinfo", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.Header.Get("Authorization") != "Bearer __SESSION_ID__" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"sub": "http://` + r.Host + `/id/` + testOrgID + `/` + testUserID + `",
+			"user_id": "` + testUserID + `",
+			"organization_id": "` + testOrgID + `",
+			"preferred_username": "` + testUsername + `",
+			"name": "Test User",
+			"email": "user@example.com",
+			"locale": "en_US",
+			"zoneinfo": "Europe/Berlin",
+			"active": true,
+			"urls": {"enterprise": "http://example.com/enterprise"}
+		}`))
+	})
+
+	client := newTestClient(t, mux)
+
+	// Negative: not logged in.
+	if _, err := client.CurrentUser(ctx); !errors.Is(err, ErrAuthentication) {
+		t.Fatalf("expected ErrAuthentication, got %v", err)
+	}
+
+	client.SetSidLoc("__SESSION_ID__", client.baseURL)
+	for i := 0; i < 2; i++ {
+		identity, err := client.CurrentUser(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if identity.UserID != testUserID || identity.Username != testUsername || identity.Timezone != "Europe/Berlin" ||
+			identity.DisplayName != "Test User" || !identity.Active || identity.URLs["enterprise"] == "" {
+			t.Fatalf("unexpected identity %+v", identity)
+		}
+	}
+	if calls != 1 {
+		t.Fatalf("expected the identity to be cached, got %d calls", calls)
+	}
+	if client.user.name != testUsername {
+		t.Fatalf("user was not populated")
+	}
+
+	// A new session invalidates the cache.
+	client.SetSidLoc("__OTHER_SESSION_ID__", client.baseURL)
+	if _, err := client.CurrentUser(ctx); err == nil {
+		t.Fatal("expected error")
+	}
+}
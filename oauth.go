@@ -0,0 +1,397 @@
+package simpleforce
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+const (
+	jwtBearerGrantType    = "urn:ietf:params:oauth:grant-type:jwt-bearer"
+	refreshTokenGrantType = "refresh_token"
+	clientCredentialsType = "client_credentials"
+
+	// jwtAssertionLifetime is how long a signed assertion stays valid. Salesforce only requires it to be valid at the
+	// time it is exchanged, so keep it short.
+	jwtAssertionLifetime = 3 * time.Minute
+)
+
+// tokenResponse holds the response data from the OAuth 2.0 token endpoint.
+type tokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	InstanceURL  string `json:"instance_url"`
+	ID           string `json:"id"`
+	IssuedAt     string `json:"issued_at"`
+	Signature    string `json:"signature"`
+	Scope        string `json:"scope"`
+	TokenType    string `json:"token_type"`
+}
+
+// JWTOption customizes LoginJWT.
+type JWTOption func(options *jwtOptions)
+
+type jwtOptions struct {
+	audience string
+}
+
+// WithJWTAudience sets the audience of the JWT assertion, which defaults to the login URL of the client. Salesforce
+// expects https://login.salesforce.com, https://test.salesforce.com for sandboxes, or the URL of an Experience Cloud
+// site, so set it when the client logs in through a My Domain URL.
+func WithJWTAudience(audience string) JWTOption {
+	return func(options *jwtOptions) {
+		options.audience = audience
+	}
+}
+
+// LoginJWT signs into salesforce using the OAuth 2.0 JWT bearer flow. consumerKey is the consumer key of the connected
+// app, username is the user to sign in as, and privateKey signs the assertion. The certificate of privateKey must be
+// uploaded to the connected app and the user must be pre-authorized for it. opts customize the assertion, e.g.
+// WithJWTAudience.
+// Ref: https://help.salesforce.com/s/articleView?id=sf.remoteaccess_oauth_jwt_flow.htm
+func (client *Client) LoginJWT(ctx context.Context, consumerKey, username string, privateKey *rsa.PrivateKey,
+	opts ...JWTOption) error {
+	l := ctxzap.Extract(ctx)
+
+	options := jwtOptions{audience: client.baseURL}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	// A fresh assertion is signed for every exchange so the session can be renewed after the assertion expires.
+	return client.loginOAuth(ctx, func(ctx context.Context) (*tokenResponse, error) {
+		assertion, err := signJWTAssertion(consumerKey, username, options.audience, privateKey)
+		if err != nil {
+			l.Error("error occurred signing jwt assertion", zap.Error(err))
+			return nil, err
+		}
+
+		form := url.Values{}
+		form.Set("grant_type", jwtBearerGrantType)
+		form.Set("assertion", assertion)
+		return client.requestToken(ctx, form)
+	})
+}
+
+// LoginRefreshToken signs into salesforce using the OAuth 2.0 refresh token flow. consumerKey and consumerSecret
+// identify the connected app which issued refreshToken; consumerSecret may be empty if the connected app doesn't
+// require it for refresh. The client holds on to the refresh token and transparently renews the session whenever
+// salesforce reports it as expired.
+// Ref: https://help.salesforce.com/s/articleView?id=sf.remoteaccess_oauth_refresh_token_flow.htm
+func (client *Client) LoginRefreshToken(ctx context.Context, consumerKey, consumerSecret, refreshToken string) error {
+	// The refresh token is only adopted along with the session it grants, so a failed login leaves the current session
+	// as it is. The grant never runs concurrently: renewals are serialized by renewMu.
+	return client.loginOAuth(ctx, func(ctx context.Context) (*tokenResponse, error) {
+		form := url.Values{}
+		form.Set("grant_type", refreshTokenGrantType)
+		form.Set("client_id", consumerKey)
+		if consumerSecret != "" {
+			form.Set("client_secret", consumerSecret)
+		}
+		form.Set("refresh_token", refreshToken)
+		tok, err := client.requestToken(ctx, form)
+		if err != nil {
+			return nil, err
+		}
+
+		// Refresh tokens are only returned when they're rotated, in which case the rotated token renews the session.
+		if tok.RefreshToken != "" {
+			refreshToken = tok.RefreshToken
+		} else {
+			tok.RefreshToken = refreshToken
+		}
+		return tok, nil
+	})
+}
+
+// LoginClientCredentials signs into salesforce using the OAuth 2.0 client credentials flow, as the execution user
+// configured on the connected app identified by consumerKey and consumerSecret. The flow is only available on My
+// Domain login URLs, so the client must have been created with the My Domain URL of the org.
+// Ref: https://help.salesforce.com/s/articleView?id=sf.remoteaccess_oauth_client_credentials_flow.htm
+func (client *Client) LoginClientCredentials(ctx context.Context, consumerKey, consumerSecret string) error {
+	return client.loginOAuth(ctx, func(ctx context.Context) (*tokenResponse, error) {
+		form := url.Values{}
+		form.Set("grant_type", clientCredentialsType)
+		form.Set("client_id", consumerKey)
+		form.Set("client_secret", consumerSecret)
+		return client.requestToken(ctx, form)
+	})
+}
+
+// loginOAuth signs in with the OAuth grant and keeps it around to renew the session once it expires. A session
+// restored from the token store is kept, and the grant is only used once that session expires.
+func (client *Client) loginOAuth(ctx context.Context, grant func(ctx context.Context) (*tokenResponse, error)) error {
+	l := ctxzap.Extract(ctx)
+
+	client.sessionMu.Lock()
+	restored := client.restoredSession
+	client.restoredSession = false
+	client.sessionMu.Unlock()
+
+	if restored {
+		client.setRenewToken(grant)
+
+		// Loading the identity verifies the restored session, renewing it if it has expired.
+		err := client.loadIdentity(ctx)
+		if err != nil {
+			return err
+		}
+
+		l.Info("User authenticated with stored session", zap.String("user", client.getUserName()))
+		return nil
+	}
+
+	tok, err := grant(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = client.applyToken(ctx, tok)
+	if err != nil {
+		return err
+	}
+	client.setRenewToken(grant)
+	client.saveToken(ctx)
+
+	l.Info("User authenticated", zap.String("user", client.getUserName()))
+	return nil
+}
+
+// renewSession renews the session by re-running the OAuth grant. staleSessionID is the session ID which salesforce
+// rejected; if another caller has already replaced it while waiting for the lock, the session is not renewed again.
+func (client *Client) renewSession(ctx context.Context, staleSessionID string) error {
+	l := ctxzap.Extract(ctx)
+
+	client.renewMu.Lock()
+	defer client.renewMu.Unlock()
+
+	if client.getSessionID() != staleSessionID {
+		return nil
+	}
+
+	// Another process sharing the token store may have renewed the session already.
+	stored := client.loadStoredToken(ctx)
+	if stored != nil && stored.AccessToken != "" && stored.AccessToken != staleSessionID {
+		l.Debug("Adopting session renewed by another client")
+		client.adoptToken(stored)
+		return nil
+	}
+
+	renewToken := client.getRenewToken()
+	if renewToken == nil {
+		return ErrAuthentication
+	}
+
+	l.Debug("Renewing expired session")
+	tok, err := renewToken(ctx)
+	if err != nil {
+		l.Error("error occurred renewing session", zap.Error(err))
+		return err
+	}
+
+	client.setToken(tok)
+	client.saveToken(ctx)
+	return nil
+}
+
+// ParseRSAPrivateKey decodes a PEM encoded RSA private key in either PKCS #1 or PKCS #8 form, as used by LoginJWT.
+func ParseRSAPrivateKey(pemData []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(pemData)
+	if block == nil {
+		return nil, errors.New("no PEM data found")
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("private key is not an RSA key")
+	}
+	return rsaKey, nil
+}
+
+// signJWTAssertion builds and signs (RS256) the JWT assertion exchanged by LoginJWT.
+func signJWTAssertion(consumerKey, username, audience string, privateKey *rsa.PrivateKey) (string, error) {
+	if privateKey == nil {
+		return "", errors.New("private key is required")
+	}
+
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	if err != nil {
+		return "", err
+	}
+	claims, err := json.Marshal(map[string]interface{}{
+		"iss": consumerKey,
+		"sub": username,
+		"aud": audience,
+		"exp": time.Now().Add(jwtAssertionLifetime).Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	encoding := base64.RawURLEncoding
+	signingInput := encoding.EncodeToString(header) + "." + encoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(signingInput))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, privateKey, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", err
+	}
+
+	return signingInput + "." + encoding.EncodeToString(signature), nil
+}
+
+// revokeToken revokes an OAuth access or refresh token. Revoking a refresh token also revokes the access tokens issued
+// with it.
+// Ref: https://help.salesforce.com/s/articleView?id=sf.remoteaccess_revoke_token.htm
+func (client *Client) revokeToken(ctx context.Context, token string) error {
+	l := ctxzap.Extract(ctx)
+
+	form := url.Values{}
+	form.Set("token", token)
+
+	requestUrl := fmt.Sprintf("%s/services/oauth2/revoke", client.baseURL)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, requestUrl, strings.NewReader(form.Encode()))
+	if err != nil {
+		l.Error("error occurred creating request", zap.Error(err))
+		return err
+	}
+	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := client.httpClient.Do(req)
+	if err != nil {
+		return parseUhttpError(ctx, resp, err)
+	}
+	defer resp.Body.Close()
+	return nil
+}
+
+// isOAuthSession returns if the session was issued by an OAuth flow rather than the SOAP login.
+func (client *Client) isOAuthSession() bool {
+	client.sessionMu.RLock()
+	defer client.sessionMu.RUnlock()
+	return client.identityURL != "" || client.refreshToken != ""
+}
+
+// getIdentityURL returns the identity URL of the OAuth session, or "" if it's unknown.
+func (client *Client) getIdentityURL() string {
+	client.sessionMu.RLock()
+	defer client.sessionMu.RUnlock()
+	return client.identityURL
+}
+
+// getRefreshToken returns the OAuth refresh token of the session, or "" if there is none.
+func (client *Client) getRefreshToken() string {
+	client.sessionMu.RLock()
+	defer client.sessionMu.RUnlock()
+	return client.refreshToken
+}
+
+// getRenewToken returns the OAuth grant renewing the session, or nil if the session can't be renewed.
+func (client *Client) getRenewToken() func(ctx context.Context) (*tokenResponse, error) {
+	client.sessionMu.RLock()
+	defer client.sessionMu.RUnlock()
+	return client.renewToken
+}
+
+// setRenewToken sets the OAuth grant renewing the session.
+func (client *Client) setRenewToken(grant func(ctx context.Context) (*tokenResponse, error)) {
+	client.sessionMu.Lock()
+	defer client.sessionMu.Unlock()
+	client.renewToken = grant
+}
+
+// requestToken posts the grant in form to the OAuth 2.0 token endpoint of the login URL.
+func (client *Client) requestToken(ctx context.Context, form url.Values) (*tokenResponse, error) {
+	l := ctxzap.Extract(ctx)
+
+	requestUrl := fmt.Sprintf("%s/services/oauth2/token", client.baseURL)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, requestUrl, strings.NewReader(form.Encode()))
+	if err != nil {
+		l.Error("error occurred creating request", zap.Error(err))
+		return nil, err
+	}
+	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Add("Accept", "application/json")
+
+	l.Debug("Sending OAuth token request",
+		zap.String("request_url", requestUrl),
+		zap.String("grant_type", form.Get("grant_type")))
+
+	resp, err := client.httpClient.Do(req)
+	if err != nil {
+		return nil, parseUhttpError(ctx, resp, err)
+	}
+	defer resp.Body.Close()
+
+	respData, err := io.ReadAll(resp.Body)
+	if err != nil {
+		l.Error("error occurred reading response data", zap.Error(err))
+		return nil, err
+	}
+
+	var tok tokenResponse
+	err = json.Unmarshal(respData, &tok)
+	if err != nil {
+		l.Error("error occurred parsing token response", zap.Error(err))
+		return nil, err
+	}
+	if tok.AccessToken == "" || tok.InstanceURL == "" {
+		return nil, ErrAuthentication
+	}
+
+	return &tok, nil
+}
+
+// issuedAt returns the time the token was issued, or the zero time if it's unknown.
+func (tok *tokenResponse) issuedAt() time.Time {
+	// issued_at is in milliseconds since the Unix epoch.
+	millis, err := strconv.ParseInt(tok.IssuedAt, 10, 64)
+	if err != nil {
+		return time.Time{}
+	}
+	return time.UnixMilli(millis)
+}
+
+// setToken adopts the session of a token response.
+func (client *Client) setToken(tok *tokenResponse) {
+	client.sessionMu.Lock()
+	defer client.sessionMu.Unlock()
+	client.sessionID = tok.AccessToken
+	client.instanceURL = strings.TrimRight(tok.InstanceURL, "/")
+	client.issuedAt = tok.issuedAt()
+	client.identityURL = tok.ID
+	// Refresh tokens are only returned when they're issued or rotated.
+	if tok.RefreshToken != "" {
+		client.refreshToken = tok.RefreshToken
+	}
+}
+
+// applyToken adopts the session of a token response and populates the user from its identity URL.
+func (client *Client) applyToken(ctx context.Context, tok *tokenResponse) error {
+	client.setToken(tok)
+	return client.loadIdentity(ctx)
+}
@@ -0,0 +1,461 @@
+package simpleforce
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+const (
+	testUserID   = "005000000000001AAA"
+	testOrgID    = "00D000000000001AAA"
+	testUsername = "user@example.com"
+)
+
+// newTestOAuthServer returns a handler serving an OAuth token endpoint and identity URL. grant is called with each
+// token request and returns the access token to issue, or "" to reject the request.
+func newTestOAuthServer(t *testing.T, grant func(r *http.Request) string) *http.ServeMux {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/services/oauth2/token", func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Error(err)
+		}
+		accessToken := grant(r)
+		w.Header().Set("Content-Type", "application/json")
+		if accessToken == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":"invalid_grant","error_description":"authentication failure"}`))
+			return
+		}
+		serverURL := "http://" + r.Host
+		_ = json.NewEncoder(w).Encode(tokenResponse{
+			AccessToken: accessToken,
+			InstanceURL: serverURL,
+			ID:          serverURL + "/id/" + testOrgID + "/" + testUserID,
+			IssuedAt:    "1700000000000",
+			TokenType:   "Bearer",
+		})
+	})
+	mux.HandleFunc("/id/"+testOrgID+"/"+testUserID, func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "Bearer ") {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"user_id":         testUserID,
+			"organization_id": testOrgID,
+			"username":        testUsername,
+			"display_name":    "Test User",
+			"email":           "user@example.com",
+			"locale":          "en_US",
+			"timezone":        "America/Los_Angeles",
+			"active":          true,
+			"urls": map[string]string{
+				"rest": "http://" + r.Host + "/services/data/v{version}/",
+			},
+		})
+	})
+	return mux
+}
+
+// decodeJWTSegment decodes a segment of a JWT. It is called from HTTP handlers, so failures are reported with t.Error
+// and nil is returned.
+func decodeJWTSegment(t *testing.T, segment string) map[string]interface{} {
+	t.Helper()
+
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		t.Error(err)
+		return nil
+	}
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Error(err)
+		return nil
+	}
+	return decoded
+}
+
+func TestClient_LoginJWT(t *testing.T) {
+	ctx := context.Background()
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var audience string
+	mux := newTestOAuthServer(t, func(r *http.Request) string {
+		if r.PostForm.Get("grant_type") != jwtBearerGrantType {
+			t.Errorf("unexpected grant type %q", r.PostForm.Get("grant_type"))
+			return ""
+		}
+
+		parts := strings.Split(r.PostForm.Get("assertion"), ".")
+		if len(parts) != 3 {
+			t.Errorf("malformed assertion")
+			return ""
+		}
+		signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+		if err != nil {
+			t.Error(err)
+			return ""
+		}
+		digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+		if err := rsa.VerifyPKCS1v15(&privateKey.PublicKey, crypto.SHA256, digest[:], signature); err != nil {
+			return ""
+		}
+
+		claims := decodeJWTSegment(t, parts[1])
+		if claims["iss"] != "__CONSUMER_KEY__" || claims["sub"] != testUsername || claims["aud"] != audience {
+			t.Errorf("unexpected claims %v", claims)
+			return ""
+		}
+		return "__ACCESS_TOKEN__"
+	})
+
+	client := newTestClient(t, mux)
+	audience = client.baseURL
+
+	err = client.LoginJWT(ctx, "__CONSUMER_KEY__", testUsername, privateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if client.sessionID != "__ACCESS_TOKEN__" || client.instanceURL != client.baseURL {
+		t.Fatalf("unexpected session %q at %q", client.sessionID, client.instanceURL)
+	}
+	if client.user.id != testUserID || client.user.name != testUsername || client.user.fullName != "Test User" {
+		t.Fatalf("unexpected user %+v", client.user)
+	}
+
+	// Negative: assertion signed with a key unknown to the connected app.
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = client.LoginJWT(ctx, "__CONSUMER_KEY__", testUsername, otherKey)
+	var sfErr SalesforceError
+	if !errors.As(err, &sfErr) || sfErr.ErrorCode != "invalid_grant" {
+		t.Fatalf("expected invalid_grant, got %v", err)
+	}
+
+	// The audience can differ from the login URL, e.g. for My Domain login URLs.
+	audience = "https://login.salesforce.com"
+	err = client.LoginJWT(ctx, "__CONSUMER_KEY__", testUsername, privateKey, WithJWTAudience(audience))
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestParseRSAPrivateKey(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, block := range []*pem.Block{
+		{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(privateKey)},
+		{Type: "PRIVATE KEY", Bytes: pkcs8},
+	} {
+		parsed, err := ParseRSAPrivateKey(pem.EncodeToMemory(block))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !parsed.Equal(privateKey) {
+			t.Fatalf("parsed %s does not match", block.Type)
+		}
+	}
+
+	if _, err := ParseRSAPrivateKey([]byte("not a key")); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestClient_LoginRefreshToken(t *testing.T) {
+	ctx := context.Background()
+
+	var (
+		mu          sync.Mutex
+		issued      int
+		validToken  string
+		tokenErrors int
+	)
+	mux := newTestOAuthServer(t, func(r *http.Request) string {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if r.PostForm.Get("grant_type") != refreshTokenGrantType ||
+			r.PostForm.Get("client_id") != "__CONSUMER_KEY__" ||
+			r.PostForm.Get("client_secret") != "__CONSUMER_SECRET__" ||
+			r.PostForm.Get("refresh_token") != "__REFRESH_TOKEN__" {
+			tokenErrors++
+			return ""
+		}
+
+		issued++
+		validToken = fmt.Sprintf("__ACCESS_TOKEN_%d__", issued)
+		return validToken
+	})
+	mux.HandleFunc("/services/data/v"+DefaultAPIVersion+"/query", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		valid := r.Header.Get("Authorization") == "Bearer "+validToken
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		if !valid {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`[{"message":"Session expired or invalid","errorCode":"INVALID_SESSION_ID"}]`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"totalSize":0,"done":true,"records":[]}`))
+	})
+
+	client := newTestClient(t, mux)
+	err := client.LoginRefreshToken(ctx, "__CONSUMER_KEY__", "__CONSUMER_SECRET__", "__REFRESH_TOKEN__")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if client.GetSid() != "__ACCESS_TOKEN_1__" || client.user.id != testUserID {
+		t.Fatalf("unexpected session %q for user %q", client.GetSid(), client.user.id)
+	}
+
+	// Expire the session, then let a burst of queries find out at the same time.
+	mu.Lock()
+	validToken = "__EXPIRED__"
+	mu.Unlock()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.Query(ctx, "SELECT Id FROM User"); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if issued != 2 || tokenErrors != 0 {
+		t.Fatalf("expected exactly one renewal, got %d tokens issued and %d rejected", issued, tokenErrors)
+	}
+	if client.GetSid() != "__ACCESS_TOKEN_2__" {
+		t.Fatalf("unexpected session %q", client.GetSid())
+	}
+
+	// Negative: a session which can't be renewed fails with the original error.
+	client.renewToken = nil
+	mu.Lock()
+	validToken = "__EXPIRED__"
+	mu.Unlock()
+	_, err = client.Query(ctx, "SELECT Id FROM User")
+	if !isInvalidSession(err) {
+		t.Fatalf("expected INVALID_SESSION_ID, got %v", err)
+	}
+}
+
+func TestClient_LoginRefreshTokenFailure(t *testing.T) {
+	ctx := context.Background()
+
+	mux := newTestOAuthServer(t, func(r *http.Request) string {
+		return ""
+	})
+
+	// A failed login keeps the session it was meant to replace, which can't be revoked or used with singleaccess.
+	client := newLoggedInTestClient(t, mux)
+	err := client.LoginRefreshToken(ctx, "__CONSUMER_KEY__", "__CONSUMER_SECRET__", "__REVOKED_REFRESH_TOKEN__")
+	if err == nil {
+		t.Fatal("expected the login to fail")
+	}
+	if client.GetSid() != "__SESSION_ID__" || client.isOAuthSession() || client.getRefreshToken() != "" {
+		t.Fatalf("unexpected session %q with refresh token %q", client.GetSid(), client.getRefreshToken())
+	}
+}
+
+func TestClient_LoginClientCredentials(t *testing.T) {
+	ctx := context.Background()
+
+	mux := newTestOAuthServer(t, func(r *http.Request) string {
+		if r.PostForm.Get("grant_type") != clientCredentialsType ||
+			r.PostForm.Get("client_id") != "__CONSUMER_KEY__" ||
+			r.PostForm.Get("client_secret") != "__CONSUMER_SECRET__" {
+			return ""
+		}
+		return "__ACCESS_TOKEN__"
+	})
+
+	client := newTestClient(t, mux)
+	err := client.LoginClientCredentials(ctx, "__CONSUMER_KEY__", "__CONSUMER_SECRET__")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if client.GetSid() != "__ACCESS_TOKEN__" || client.GetLoc() != client.baseURL {
+		t.Fatalf("unexpected session %q at %q", client.GetSid(), client.GetLoc())
+	}
+	if client.identityURL != client.baseURL+"/id/"+testOrgID+"/"+testUserID {
+		t.Fatalf("unexpected identity URL %q", client.identityURL)
+	}
+	if client.issuedAt.UnixMilli() != 1700000000000 {
+		t.Fatalf("unexpected issued at %v", client.issuedAt)
+	}
+	if client.user.id != testUserID || client.user.email != "user@example.com" {
+		t.Fatalf("unexpected user %+v", client.user)
+	}
+
+	// Negative: wrong consumer secret.
+	err = client.LoginClientCredentials(ctx, "__CONSUMER_KEY__", "__WRONG_SECRET__")
+	if err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestClient_RenewOntoNewInstance(t *testing.T) {
+	ctx := context.Background()
+
+	var (
+		mu            sync.Mutex
+		issued        int
+		validToken    string
+		validInstance string
+		instances     [2]string
+	)
+	// Every instance only accepts the tokens issued for it.
+	newInstance := func(i int) {
+		mux := http.NewServeMux()
+		mux.HandleFunc("/services/data/v"+DefaultAPIVersion+"/query", func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			valid := validInstance == instances[i] && r.Header.Get("Authorization") == "Bearer "+validToken
+			mu.Unlock()
+
+			w.Header().Set("Content-Type", "application/json")
+			if !valid {
+				w.WriteHeader(http.StatusUnauthorized)
+				_, _ = w.Write([]byte(`[{"message":"Session expired or invalid","errorCode":"INVALID_SESSION_ID"}]`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"totalSize":0,"done":true,"records":[]}`))
+		})
+		server := httptest.NewServer(mux)
+		t.Cleanup(server.Close)
+		instances[i] = server.URL
+	}
+	newInstance(0)
+	newInstance(1)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/services/oauth2/token", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		// Every renewal moves the session to the other instance.
+		validInstance = instances[issued%2]
+		issued++
+		validToken = fmt.Sprintf("__ACCESS_TOKEN_%d__", issued)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(tokenResponse{
+			AccessToken: validToken,
+			InstanceURL: validInstance,
+			TokenType:   "Bearer",
+		})
+	})
+
+	client := newTestClient(t, mux)
+	if err := client.LoginClientCredentials(ctx, "__CONSUMER_KEY__", "__CONSUMER_SECRET__"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Read the session continuously while it is renewed.
+	done := make(chan struct{})
+	var readers sync.WaitGroup
+	readers.Add(1)
+	go func() {
+		defer readers.Done()
+		for {
+			select {
+			case <-done:
+				return
+			default:
+				_ = client.makeURL("query")
+				_ = client.isOAuthSession()
+			}
+		}
+	}()
+
+	for round := 0; round < 5; round++ {
+		mu.Lock()
+		validToken = "__EXPIRED__"
+		mu.Unlock()
+
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if _, err := client.Query(ctx, "SELECT Id FROM User"); err != nil {
+					t.Error(err)
+				}
+			}()
+		}
+		wg.Wait()
+	}
+	close(done)
+	readers.Wait()
+
+	if issued != 6 || client.GetLoc() != instances[1] {
+		t.Fatalf("unexpected %d tokens issued, session at %q", issued, client.GetLoc())
+	}
+}
+
+func TestClient_SetSidLocAfterOAuth(t *testing.T) {
+	ctx := context.Background()
+
+	var issued int
+	mux := newTestOAuthServer(t, func(r *http.Request) string {
+		issued++
+		return "__ACCESS_TOKEN__"
+	})
+	mux.HandleFunc("/services/data/v"+DefaultAPIVersion+"/query", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`[{"message":"Session expired or invalid","errorCode":"INVALID_SESSION_ID"}]`))
+	})
+
+	client := newTestClient(t, mux)
+	if err := client.LoginRefreshToken(ctx, "__CONSUMER_KEY__", "", "__REFRESH_TOKEN__"); err != nil {
+		t.Fatal(err)
+	}
+	client.SetSidLoc("__SESSION_ID__", client.baseURL)
+
+	// The injected session is neither renewed with the old grant nor treated as an OAuth session.
+	if _, err := client.Query(ctx, "SELECT Id FROM User"); !isInvalidSession(err) {
+		t.Fatalf("expected INVALID_SESSION_ID, got %v", err)
+	}
+	if issued != 1 || client.GetSid() != "__SESSION_ID__" {
+		t.Fatalf("session was renewed: %d tokens issued, session %q", issued, client.GetSid())
+	}
+	if client.isOAuthSession() || client.getIdentityURL() != "" || client.user.id != "" {
+		t.Fatalf("OAuth state was kept: identity URL %q, user %+v", client.getIdentityURL(), client.user)
+	}
+}
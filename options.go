@@ -0,0 +1,60 @@
+package simpleforce
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Headers of the call options supported by the REST API.
+// Ref: https://developer.salesforce.com/docs/atlas.en-us.api_rest.meta/api_rest/headers.htm
+const (
+	headerQueryOptions = "Sforce-Query-Options"
+	headerCallOptions  = "Sforce-Call-Options"
+	headerAutoAssign   = "Sforce-Auto-Assign"
+)
+
+// CallOption customizes the request headers of a single API call, e.g. to set the batch size of a query. GET requests
+// with call options carry a hash of their headers in the _headers query parameter, so that the response cache of the
+// uhttp client, which doesn't tell their headers apart, doesn't answer them with the response of another request.
+type CallOption func(header http.Header)
+
+// WithBatchSize sets the number of records returned per batch of a query. Salesforce accepts values from 200 to 2000,
+// and may return smaller batches, e.g. when querying many fields.
+// Ref: https://developer.salesforce.com/docs/atlas.en-us.api_rest.meta/api_rest/headers_queryoptions.htm
+func WithBatchSize(size int) CallOption {
+	return func(header http.Header) {
+		addHeaderOption(header, headerQueryOptions, "batchSize="+strconv.Itoa(size))
+	}
+}
+
+// WithCallClient identifies the client making the call, e.g. to tell API usage of integrations apart.
+// Ref: https://developer.salesforce.com/docs/atlas.en-us.api_rest.meta/api_rest/headers_calloptions.htm
+func WithCallClient(name string) CallOption {
+	return func(header http.Header) {
+		addHeaderOption(header, headerCallOptions, "client="+name)
+	}
+}
+
+// WithAutoAssign sets whether assignment rules are applied when creating or updating cases and leads.
+// Ref: https://developer.salesforce.com/docs/atlas.en-us.api_rest.meta/api_rest/headers_autoassign.htm
+func WithAutoAssign(assign bool) CallOption {
+	return func(header http.Header) {
+		header.Set(headerAutoAssign, strings.ToUpper(strconv.FormatBool(assign)))
+	}
+}
+
+// WithHeader sets an arbitrary request header.
+func WithHeader(key, value string) CallOption {
+	return func(header http.Header) {
+		header.Set(key, value)
+	}
+}
+
+// addHeaderOption adds option to the comma separated list of options in the header key.
+func addHeaderOption(header http.Header, key, option string) {
+	if current := header.Get(key); current != "" {
+		option = current + ", " + option
+	}
+	header.Set(key, option)
+}
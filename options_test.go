@@ -0,0 +1,51 @@
+package simpleforce
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestClient_QueryBatchSize(t *testing.T) {
+	ctx := context.Background()
+
+	server := newTestQueryServer(t, 5, 2)
+	client := newLoggedInTestClient(t, server)
+
+	result, err := client.Query(ctx, "SELECT Id FROM User", WithBatchSize(4), WithCallClient("sync"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Records) != 4 {
+		t.Fatalf("expected a batch of 4 records, got %d", len(result.Records))
+	}
+	header := server.requests[0].Header
+	if header.Get("Sforce-Query-Options") != "batchSize=4" || header.Get("Sforce-Call-Options") != "client=sync" {
+		t.Fatalf("unexpected headers %v", header)
+	}
+
+	it := client.QueryIter(ctx, "SELECT Id FROM User", WithBatchSize(3))
+	for it.Next() {
+	}
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if server.requestCount() != 3 {
+		t.Fatalf("expected 2 batches, got %d requests", server.requestCount()-1)
+	}
+}
+
+func TestCallOptions(t *testing.T) {
+	header := http.Header{}
+	for _, opt := range []CallOption{
+		WithCallClient("sync"),
+		WithHeader("Sforce-Call-Options", "defaultNamespace=ns"),
+		WithCallClient("other"),
+		WithAutoAssign(false),
+	} {
+		opt(header)
+	}
+	if header.Get("Sforce-Call-Options") != "defaultNamespace=ns, client=other" || header.Get("Sforce-Auto-Assign") != "FALSE" {
+		t.Fatalf("unexpected headers %v", header)
+	}
+}
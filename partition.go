@@ -0,0 +1,293 @@
+package simpleforce
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/conductorone/simpleforce/soql"
+)
+
+// idAlphabet holds the digits of salesforce IDs in ascending order, which matches the order of their characters.
+const idAlphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// errIteratorClosed stops an iteration closed by Close.
+var errIteratorClosed = errors.New("iterator closed")
+
+// PartitionedQueryIterator iterates over the records of a query split into partitions, which are queried
+// concurrently. Records are returned in no particular order. It is not safe for concurrent use.
+//
+//	partitions, err := client.IDPartitions(ctx, "User", 8)
+//	it := client.QueryPartitioned(ctx, soql.Select("Id", "Name").From("User"), partitions, 4)
+//	defer it.Close()
+//	for it.Next() {
+//		record := it.Record()
+//	}
+//	if err := it.Err(); err != nil {
+//		// handle the error
+//	}
+type PartitionedQueryIterator struct {
+	ctx     context.Context
+	cancel  context.CancelFunc
+	records chan *SObject
+	record  *SObject
+	done    bool
+
+	errOnce sync.Once
+	err     error
+}
+
+// QueryPartitioned runs q once per partition, each partition adding its condition to the query, and merges the records
+// into one iterator. At most workers partitions are queried at the same time; zero or less queries all partitions at
+// once. Each partition follows all of its batches like QueryIter, with opts applied to all requests. The partitions
+// must not overlap, or records are returned more than once.
+//
+// Partitions are usually created with IDPartitions or DateTimePartitions. Call Close when stopping before the end of
+// the iteration, to stop the pending queries.
+func (client *Client) QueryPartitioned(ctx context.Context, q *soql.Query, partitions []soql.Condition, workers int,
+	opts ...CallOption) *PartitionedQueryIterator {
+	ctx, cancel := context.WithCancel(ctx)
+	it := &PartitionedQueryIterator{
+		ctx:     ctx,
+		cancel:  cancel,
+		records: make(chan *SObject, 100),
+	}
+
+	if workers <= 0 || workers > len(partitions) {
+		workers = len(partitions)
+	}
+
+	queries := make(chan string, len(partitions))
+	for _, partition := range partitions {
+		queries <- q.Clone().Where(partition).String()
+	}
+	close(queries)
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for query := range queries {
+				if !it.queryPartition(client, query, opts) {
+					return
+				}
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(it.records)
+	}()
+
+	return it
+}
+
+// queryPartition sends the records of query to the iterator. It returns false if the iteration must stop.
+func (it *PartitionedQueryIterator) queryPartition(client *Client, query string, opts []CallOption) bool {
+	partition := client.QueryIter(it.ctx, query, opts...)
+	for partition.Next() {
+		select {
+		case it.records <- partition.Record():
+		case <-it.ctx.Done():
+			return false
+		}
+	}
+	if err := partition.Err(); err != nil {
+		it.setErr(err)
+		return false
+	}
+	return true
+}
+
+// setErr stops the iteration with err, unless it was stopped already.
+func (it *PartitionedQueryIterator) setErr(err error) {
+	it.errOnce.Do(func() {
+		it.err = err
+		it.cancel()
+	})
+}
+
+// Next advances the iterator to the next record, which is then available through Record. It returns false when all
+// partitions are done or an error occurred, which is reported by Err.
+func (it *PartitionedQueryIterator) Next() bool {
+	if it.done {
+		return false
+	}
+	record, ok := <-it.records
+	if !ok {
+		it.done = true
+		it.record = nil
+		// The context may be done without any partition noticing, e.g. if it was canceled after the last request.
+		if err := it.ctx.Err(); err != nil {
+			it.setErr(err)
+		}
+		it.cancel()
+		return false
+	}
+	it.record = record
+	return true
+}
+
+// Record returns the current record, or nil if Next hasn't been called or returned false.
+func (it *PartitionedQueryIterator) Record() *SObject {
+	return it.record
+}
+
+// Decode decodes the current record of the iterator into v as done by QueryIterator.Decode.
+func (it *PartitionedQueryIterator) Decode(v interface{}) error {
+	if it.record == nil {
+		return errors.New("no current record")
+	}
+	err := it.record.FetchChildRecords(it.ctx)
+	if err != nil {
+		return err
+	}
+	return it.record.Decode(v)
+}
+
+// Err returns the error which stopped the iteration, if any. It must only be called once Next returned false.
+func (it *PartitionedQueryIterator) Err() error {
+	if errors.Is(it.err, errIteratorClosed) {
+		return nil
+	}
+	return it.err
+}
+
+// Close stops the pending queries. Next returns false afterwards.
+func (it *PartitionedQueryIterator) Close() {
+	it.setErr(errIteratorClosed)
+	// Drain the records so that the workers stop.
+	for range it.records {
+	}
+	it.done = true
+	it.record = nil
+}
+
+// DateTimePartitions splits the window from start to end into n partitions of the same length on the datetime field,
+// e.g. CreatedDate. Records outside of the window belong to none of the partitions.
+func DateTimePartitions(field string, start, end time.Time, n int) []soql.Condition {
+	if n < 1 {
+		n = 1
+	}
+	step := end.Sub(start) / time.Duration(n)
+
+	partitions := make([]soql.Condition, 0, n)
+	from := start
+	for i := 0; i < n; i++ {
+		to := from.Add(step)
+		if i == n-1 {
+			to = end
+		}
+		partitions = append(partitions, soql.And(soql.Ge(field, from), soql.Lt(field, to)))
+		from = to
+	}
+	return partitions
+}
+
+// IDPartitions splits the records of the object typeName into up to n partitions by ranges of IDs. The ranges are
+// spread evenly between the lowest and the highest ID, so the partitions are about the same size when records were
+// created at a steady pace. The first and the last partition are unbounded, so that records created meanwhile belong
+// to a partition too.
+func (client *Client) IDPartitions(ctx context.Context, typeName string, n int) ([]soql.Condition, error) {
+	first, err := client.boundaryID(ctx, typeName, "ASC")
+	if err != nil {
+		return nil, err
+	}
+	last, err := client.boundaryID(ctx, typeName, "DESC")
+	if err != nil {
+		return nil, err
+	}
+	if first == "" || last == "" || n <= 1 {
+		return []soql.Condition{soql.IsNotNull("Id")}, nil
+	}
+
+	boundaries, err := idBoundaries(first, last, n)
+	if err != nil {
+		return nil, err
+	}
+
+	partitions := make([]soql.Condition, 0, len(boundaries)+1)
+	partitions = append(partitions, soql.Lt("Id", boundaries[0]))
+	for i := 1; i < len(boundaries); i++ {
+		partitions = append(partitions, soql.And(soql.Ge("Id", boundaries[i-1]), soql.Lt("Id", boundaries[i])))
+	}
+	partitions = append(partitions, soql.Ge("Id", boundaries[len(boundaries)-1]))
+	return partitions, nil
+}
+
+// boundaryID returns the lowest or highest ID of typeName, depending on order, or "" if there are no records.
+func (client *Client) boundaryID(ctx context.Context, typeName, order string) (string, error) {
+	result, err := client.Query(ctx, fmt.Sprintf("SELECT Id FROM %s ORDER BY Id %s LIMIT 1", typeName, order))
+	if err != nil {
+		return "", err
+	}
+	if len(result.Records) == 0 {
+		return "", nil
+	}
+	return result.Records[0].ID(), nil
+}
+
+// idBoundaries returns up to n-1 distinct IDs spread evenly between the IDs first and last, in ascending order.
+func idBoundaries(first, last string, n int) ([]string, error) {
+	low, err := decodeID(first)
+	if err != nil {
+		return nil, err
+	}
+	high, err := decodeID(last)
+	if err != nil {
+		return nil, err
+	}
+
+	span := new(big.Int).Sub(high, low)
+	var boundaries []string
+	for i := 1; i < n; i++ {
+		offset := new(big.Int).Mul(span, big.NewInt(int64(i)))
+		offset.Div(offset, big.NewInt(int64(n)))
+		boundary := encodeID(offset.Add(offset, low))
+		if boundary == first || (len(boundaries) > 0 && boundary == boundaries[len(boundaries)-1]) {
+			continue
+		}
+		boundaries = append(boundaries, boundary)
+	}
+	if len(boundaries) == 0 {
+		boundaries = append(boundaries, first[:15])
+	}
+	return boundaries, nil
+}
+
+// decodeID returns the number represented by the case-sensitive 15 character form of a salesforce ID.
+func decodeID(id string) (*big.Int, error) {
+	if len(id) != 15 && len(id) != 18 {
+		return nil, fmt.Errorf("invalid ID %q", id)
+	}
+	value := new(big.Int)
+	base := big.NewInt(int64(len(idAlphabet)))
+	for _, c := range id[:15] {
+		digit := strings.IndexRune(idAlphabet, c)
+		if digit < 0 {
+			return nil, fmt.Errorf("invalid ID %q", id)
+		}
+		value.Mul(value, base)
+		value.Add(value, big.NewInt(int64(digit)))
+	}
+	return value, nil
+}
+
+// encodeID returns the 15 character ID representing value.
+func encodeID(value *big.Int) string {
+	digits := make([]byte, 15)
+	base := big.NewInt(int64(len(idAlphabet)))
+	v := new(big.Int).Set(value)
+	digit := new(big.Int)
+	for i := len(digits) - 1; i >= 0; i-- {
+		v.DivMod(v, base, digit)
+		digits[i] = idAlphabet[digit.Int64()]
+	}
+	return string(digits)
+}
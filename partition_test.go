@@ -0,0 +1,175 @@
+package simpleforce
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/conductorone/simpleforce/soql"
+)
+
+// newTestPartitionServer serves n User records, filtered by the Id conditions of the queries.
+func newTestPartitionServer(t *testing.T, n int, failing string) (http.Handler, *[]string) {
+	ids := make([]string, n)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("005%012dAAA", i*7)
+	}
+	idCondition := regexp.MustCompile(`Id (<|>=) '(\w+)'`)
+
+	var mu sync.Mutex
+	var queries []string
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query().Get("q")
+		mu.Lock()
+		queries = append(queries, q)
+		mu.Unlock()
+
+		if failing != "" && strings.Contains(q, failing) {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`[{"message":"Your query request was running for too long.","errorCode":"QUERY_TIMEOUT"}]`))
+			return
+		}
+
+		var matching []string
+		switch {
+		case strings.HasSuffix(q, "ORDER BY Id ASC LIMIT 1"):
+			matching = ids[:1]
+		case strings.HasSuffix(q, "ORDER BY Id DESC LIMIT 1"):
+			matching = ids[len(ids)-1:]
+		default:
+			for _, id := range ids {
+				ok := true
+				for _, m := range idCondition.FindAllStringSubmatch(q, -1) {
+					if (m[1] == "<" && id[:15] >= m[2]) || (m[1] == ">=" && id[:15] < m[2]) {
+						ok = false
+					}
+				}
+				if ok {
+					matching = append(matching, id)
+				}
+			}
+		}
+
+		records := make([]map[string]interface{}, len(matching))
+		for i, id := range matching {
+			records[i] = map[string]interface{}{"attributes": map[string]interface{}{"type": "User"}, "Id": id}
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"totalSize": len(records), "done": true, "records": records})
+	}), &queries
+}
+
+func TestClient_QueryPartitioned(t *testing.T) {
+	ctx := context.Background()
+
+	handler, queries := newTestPartitionServer(t, 100, "")
+	client := newLoggedInTestClient(t, handler)
+
+	partitions, err := client.IDPartitions(ctx, "User", 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(partitions) != 4 {
+		t.Fatalf("expected 4 partitions, got %v", partitions)
+	}
+
+	it := client.QueryPartitioned(ctx, soql.Select("Id").From("User").Where(soql.Eq("IsActive", true)), partitions, 2)
+	defer it.Close()
+	var ids []string
+	for it.Next() {
+		ids = append(ids, it.Record().ID())
+	}
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	sort.Strings(ids)
+	if len(ids) != 100 || ids[0] != "005000000000000AAA" || ids[99] != "005000000000693AAA" {
+		t.Fatalf("unexpected records %v", ids)
+	}
+	for i := 1; i < len(ids); i++ {
+		if ids[i] == ids[i-1] {
+			t.Fatalf("duplicate record %s", ids[i])
+		}
+	}
+	// Two queries for the boundaries, then one per partition.
+	if len(*queries) != 6 || !strings.HasPrefix((*queries)[2], "SELECT Id FROM User WHERE IsActive = true AND ") {
+		t.Fatalf("unexpected queries %v", *queries)
+	}
+}
+
+func TestClient_QueryPartitionedError(t *testing.T) {
+	ctx := context.Background()
+
+	handler, _ := newTestPartitionServer(t, 100, "Id >= ")
+	client := newLoggedInTestClient(t, handler)
+
+	partitions, err := client.IDPartitions(ctx, "User", 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	it := client.QueryPartitioned(ctx, soql.Select("Id").From("User"), partitions, 0)
+	for it.Next() {
+	}
+	var sfErr SalesforceError
+	if !errors.As(it.Err(), &sfErr) || sfErr.ErrorCode != "QUERY_TIMEOUT" {
+		t.Fatalf("expected QUERY_TIMEOUT, got %v", it.Err())
+	}
+
+	// Closing the iterator early is not an error.
+	handler, _ = newTestPartitionServer(t, 1000, "")
+	client = newLoggedInTestClient(t, handler)
+	it = client.QueryPartitioned(ctx, soql.Select("Id").From("User"), []soql.Condition{soql.IsNotNull("Id")}, 1)
+	if !it.Next() {
+		t.Fatal(it.Err())
+	}
+	it.Close()
+	if it.Next() || it.Err() != nil {
+		t.Fatalf("expected the iteration to stop without error, got %v", it.Err())
+	}
+}
+
+func TestDateTimePartitions(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	partitions := DateTimePartitions("CreatedDate", start, start.Add(72*time.Hour), 3)
+	if len(partitions) != 3 {
+		t.Fatalf("expected 3 partitions, got %v", partitions)
+	}
+	if actual := partitions[1].String(); actual != "(CreatedDate >= 2024-01-02T00:00:00Z AND CreatedDate < 2024-01-03T00:00:00Z)" {
+		t.Fatalf("unexpected partition %s", actual)
+	}
+	if actual := partitions[2].String(); actual != "(CreatedDate >= 2024-01-03T00:00:00Z AND CreatedDate < 2024-01-04T00:00:00Z)" {
+		t.Fatalf("unexpected partition %s", actual)
+	}
+}
+
+func TestIDBoundaries(t *testing.T) {
+	boundaries, err := idBoundaries("00500000000000A", "00500000000000z", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A and z are the 10th and 61st digits, so the middle is the 35th digit, Z.
+	if len(boundaries) != 1 || boundaries[0] != "00500000000000Z" {
+		t.Fatalf("unexpected boundaries %v", boundaries)
+	}
+
+	// Fewer IDs than partitions.
+	boundaries, err = idBoundaries("005000000000001AAA", "005000000000002AAA", 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(boundaries) != 1 || boundaries[0] != "005000000000001" {
+		t.Fatalf("unexpected boundaries %v", boundaries)
+	}
+
+	if _, err := idBoundaries("invalid", "005000000000002AAA", 2); err == nil {
+		t.Fatal("expected error for invalid ID")
+	}
+}
@@ -0,0 +1,201 @@
+package simpleforce
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// QueryIterator iterates over the records of an SOQL query across all batches, fetching each batch only once the
+// records of the previous one have been consumed. It is not safe for concurrent use.
+//
+//	it := client.QueryIter(ctx, "SELECT Id, Name FROM User")
+//	for it.Next() {
+//		record := it.Record()
+//	}
+//	if err := it.Err(); err != nil {
+//		// handle the error
+//	}
+type QueryIterator struct {
+	ctx      context.Context
+	client   *Client
+	resource string
+	query    string
+	opts     []CallOption
+
+	result *QueryResult // the current batch, nil until the first batch is fetched.
+	index  int          // the index of the next record in the current batch.
+	record *SObject
+	err    error
+
+	position  int    // the number of records returned by Next, including those before resuming.
+	locator   string // the nextRecordsUrl of the query without the offset, once known.
+	resumeURL string // the URL of the first batch when resuming from a locator.
+	skip      int    // the number of records to skip when resuming without a locator.
+	done      bool   // set once all records have been returned.
+}
+
+// QueryCursor records the progress of a QueryIterator. It can be serialized, e.g. as JSON, to resume the iteration
+// later with ResumeQuery, possibly in another process. Query locators expire after 15 minutes of inactivity, after
+// which the query must be run again.
+// Ref: https://developer.salesforce.com/docs/atlas.en-us.api_rest.meta/api_rest/dome_query.htm
+type QueryCursor struct {
+	// Resource is either "query" or "queryAll".
+	Resource string `json:"resource"`
+	// Query is the SOQL query.
+	Query string `json:"query"`
+	// Locator is the nextRecordsUrl of the query without the offset. It is empty if the query returned all records in
+	// a single batch, in which case resuming runs the query again.
+	Locator string `json:"locator,omitempty"`
+	// Position is the number of records consumed.
+	Position int `json:"position"`
+	// Done is set once all records have been consumed.
+	Done bool `json:"done,omitempty"`
+}
+
+// QueryIter returns an iterator over the records of the SOQL query q. No request is made until the first call to Next
+// or TotalSize, and the iteration stops with the context error once ctx is done. opts apply to the requests of all
+// batches.
+func (client *Client) QueryIter(ctx context.Context, q string, opts ...CallOption) *QueryIterator {
+	return &QueryIterator{
+		ctx:      ctx,
+		client:   client,
+		resource: "query",
+		query:    q,
+		opts:     opts,
+	}
+}
+
+// QueryAllIter returns an iterator over the records of the SOQL query q like QueryIter, including deleted and archived
+// records like QueryAll.
+func (client *Client) QueryAllIter(ctx context.Context, q string, opts ...CallOption) *QueryIterator {
+	return &QueryIterator{
+		ctx:      ctx,
+		client:   client,
+		resource: "queryAll",
+		query:    q,
+		opts:     opts,
+	}
+}
+
+// ResumeQuery returns an iterator continuing the iteration recorded by cursor, starting with the first record which
+// wasn't consumed. The iteration stops with an error wrapping ErrQueryLocatorExpired if the query locator has expired.
+func (client *Client) ResumeQuery(ctx context.Context, cursor QueryCursor, opts ...CallOption) *QueryIterator {
+	it := &QueryIterator{
+		ctx:      ctx,
+		client:   client,
+		resource: cursor.Resource,
+		query:    cursor.Query,
+		opts:     opts,
+		position: cursor.Position,
+		locator:  cursor.Locator,
+		done:     cursor.Done,
+	}
+	if it.resource == "" {
+		it.resource = "query"
+	}
+	if cursor.Locator != "" {
+		it.resumeURL = cursor.Locator + "-" + strconv.Itoa(cursor.Position)
+	} else {
+		it.skip = cursor.Position
+	}
+	return it
+}
+
+// Cursor returns the progress of the iteration, to resume it later with ResumeQuery. The record last returned by Next
+// counts as consumed.
+func (it *QueryIterator) Cursor() QueryCursor {
+	return QueryCursor{
+		Resource: it.resource,
+		Query:    it.query,
+		Locator:  it.locator,
+		Position: it.position,
+		Done:     it.done,
+	}
+}
+
+// Next advances the iterator to the next record, which is then available through Record. It returns false when there
+// are no more records or an error occurred, which is reported by Err.
+func (it *QueryIterator) Next() bool {
+	it.record = nil
+	if it.err != nil || it.done {
+		return false
+	}
+	if err := it.ctx.Err(); err != nil {
+		it.err = err
+		return false
+	}
+
+	for it.result == nil || it.index >= len(it.result.Records) {
+		if !it.fetch() {
+			it.done = it.err == nil
+			return false
+		}
+	}
+
+	it.record = &it.result.Records[it.index]
+	it.index++
+	it.position++
+	return true
+}
+
+// Record returns the current record, or nil if Next hasn't been called or returned false.
+func (it *QueryIterator) Record() *SObject {
+	return it.record
+}
+
+// Err returns the error which stopped the iteration, if any.
+func (it *QueryIterator) Err() error {
+	return it.err
+}
+
+// TotalSize returns the total number of records matched by the query, fetching the first batch if needed. It returns
+// 0 if the first batch couldn't be fetched, in which case Err reports why.
+func (it *QueryIterator) TotalSize() int {
+	if it.result == nil && it.err == nil && !it.done {
+		it.fetch()
+	}
+	if it.result == nil {
+		return 0
+	}
+	return it.result.TotalSize
+}
+
+// fetch requests the next batch. It returns false if there is none or the request failed.
+func (it *QueryIterator) fetch() bool {
+	q := it.query
+	if it.result != nil {
+		if it.result.Done || it.result.NextRecordsURL == "" {
+			return false
+		}
+		q = it.result.NextRecordsURL
+	} else if it.resumeURL != "" {
+		q = it.resumeURL
+	}
+
+	result, err := it.client.query(it.ctx, it.resource, q, it.opts...)
+	if err != nil {
+		if q != it.query && isInvalidQueryLocator(err) {
+			err = fmt.Errorf("%w: %w", ErrQueryLocatorExpired, err)
+		}
+		it.err = err
+		return false
+	}
+
+	if result.NextRecordsURL != "" {
+		if i := strings.LastIndex(result.NextRecordsURL, "-"); i > 0 {
+			it.locator = result.NextRecordsURL[:i]
+		}
+	}
+
+	it.result = result
+	it.index = 0
+
+	// When resuming without a locator, the query was run again and the records consumed before must be skipped.
+	if it.skip > 0 {
+		it.index = min(it.skip, len(result.Records))
+		it.skip -= it.index
+	}
+	return true
+}
@@ -0,0 +1,307 @@
+package simpleforce
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+const testQueryLocator = "01g000000000001AAA"
+
+// testQueryServer serves records through the query endpoints in batches of batchSize, or of the size requested with
+// the Sforce-Query-Options header, paging with query locators the way salesforce does. It keeps track of the requests
+// it serves.
+type testQueryServer struct {
+	t         *testing.T
+	records   []map[string]interface{}
+	batchSize int
+
+	mu       sync.Mutex
+	requests []*http.Request
+}
+
+// newTestQueryServer creates a testQueryServer serving n User records.
+func newTestQueryServer(t *testing.T, n, batchSize int) *testQueryServer {
+	records := make([]map[string]interface{}, n)
+	for i := range records {
+		id := fmt.Sprintf("005%015d", i)
+		records[i] = map[string]interface{}{
+			"attributes": map[string]interface{}{
+				"type": "User",
+				"url":  "/services/data/v" + DefaultAPIVersion + "/sobjects/User/" + id,
+			},
+			"Id":   id,
+			"Name": fmt.Sprintf("User %d", i),
+		}
+	}
+	return &testQueryServer{t: t, records: records, batchSize: batchSize}
+}
+
+func (s *testQueryServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.requests = append(s.requests, r)
+	s.mu.Unlock()
+
+	prefix := "/services/data/v" + DefaultAPIVersion + "/"
+	path := strings.TrimPrefix(r.URL.Path, prefix)
+
+	offset := 0
+	switch {
+	case path == "query" || path == "queryAll":
+		if r.URL.Query().Get("q") == "" {
+			s.t.Errorf("missing query in %s", r.URL)
+		}
+	case strings.HasPrefix(path, "query/"+testQueryLocator+"-"):
+		var err error
+		offset, err = strconv.Atoi(strings.TrimPrefix(path, "query/"+testQueryLocator+"-"))
+		if err != nil || offset > len(s.records) {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`[{"message":"invalid query locator","errorCode":"INVALID_QUERY_LOCATOR"}]`))
+			return
+		}
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`[{"message":"The requested resource does not exist","errorCode":"NOT_FOUND"}]`))
+		return
+	}
+
+	batchSize := s.batchSize
+	if options := r.Header.Get("Sforce-Query-Options"); options != "" {
+		var err error
+		batchSize, err = strconv.Atoi(strings.TrimPrefix(options, "batchSize="))
+		if err != nil {
+			s.t.Errorf("unexpected query options %q", options)
+		}
+	}
+
+	end := min(offset+batchSize, len(s.records))
+	result := map[string]interface{}{
+		"totalSize": len(s.records),
+		"done":      end == len(s.records),
+		"records":   s.records[offset:end],
+	}
+	if end < len(s.records) {
+		result["nextRecordsUrl"] = fmt.Sprintf("%squery/%s-%d", prefix, testQueryLocator, end)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(result)
+}
+
+// requestCount returns the number of requests served.
+func (s *testQueryServer) requestCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.requests)
+}
+
+func TestClient_QueryIter(t *testing.T) {
+	ctx := context.Background()
+
+	server := newTestQueryServer(t, 5, 2)
+	client := newLoggedInTestClient(t, server)
+
+	it := client.QueryIter(ctx, "SELECT Id, Name FROM User")
+	if it.TotalSize() != 5 || server.requestCount() != 1 {
+		t.Fatalf("unexpected total size %d after %d requests", it.TotalSize(), server.requestCount())
+	}
+
+	var names []string
+	for it.Next() {
+		if it.Record().Type() != "User" {
+			t.Fatalf("unexpected record type %q", it.Record().Type())
+		}
+		names = append(names, it.Record().StringField("Name"))
+	}
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if len(names) != 5 || names[0] != "User 0" || names[4] != "User 4" {
+		t.Fatalf("unexpected records %v", names)
+	}
+	if server.requestCount() != 3 {
+		t.Fatalf("expected 3 batches, got %d requests", server.requestCount())
+	}
+	if it.Next() || it.Record() != nil {
+		t.Fatal("expected the iteration to be done")
+	}
+
+	// Records returned by the iterator can be used for further requests.
+	if it := client.QueryIter(ctx, "SELECT Id FROM User"); !it.Next() || it.Record().client() != client {
+		t.Fatal("expected record associated with the client")
+	}
+}
+
+func TestClient_QueryIterStopEarly(t *testing.T) {
+	ctx := context.Background()
+
+	server := newTestQueryServer(t, 5, 2)
+	client := newLoggedInTestClient(t, server)
+
+	it := client.QueryIter(ctx, "SELECT Id, Name FROM User")
+	for i := 0; i < 2; i++ {
+		if !it.Next() {
+			t.Fatal(it.Err())
+		}
+	}
+	if server.requestCount() != 1 {
+		t.Fatalf("expected a single batch to be fetched, got %d requests", server.requestCount())
+	}
+}
+
+func TestClient_QueryIterCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	server := newTestQueryServer(t, 5, 2)
+	client := newLoggedInTestClient(t, server)
+
+	it := client.QueryIter(ctx, "SELECT Id, Name FROM User")
+	if !it.Next() {
+		t.Fatal(it.Err())
+	}
+	cancel()
+	if it.Next() {
+		t.Fatal("expected the iteration to stop")
+	}
+	if !errors.Is(it.Err(), context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", it.Err())
+	}
+	if server.requestCount() != 1 {
+		t.Fatalf("expected a single batch to be fetched, got %d requests", server.requestCount())
+	}
+}
+
+func TestClient_QueryAll(t *testing.T) {
+	ctx := context.Background()
+
+	server := newTestQueryServer(t, 3, 2)
+	server.records[0]["IsDeleted"] = false
+	server.records[1]["IsDeleted"] = true
+	server.records[2]["IsDeleted"] = false
+	client := newLoggedInTestClient(t, server)
+
+	result, err := client.QueryAll(ctx, "SELECT Id, IsDeleted FROM User")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.TotalSize != 3 || result.Records[0].IsDeleted() || !result.Records[1].IsDeleted() {
+		t.Fatalf("unexpected result %+v", result)
+	}
+	if path := server.requests[0].URL.Path; path != "/services/data/v"+DefaultAPIVersion+"/queryAll" {
+		t.Fatalf("unexpected path %q", path)
+	}
+
+	var deleted []string
+	it := client.QueryAllIter(ctx, "SELECT Id, IsDeleted FROM User")
+	for it.Next() {
+		if it.Record().IsDeleted() {
+			deleted = append(deleted, it.Record().ID())
+		}
+	}
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if len(deleted) != 1 || deleted[0] != server.records[1]["Id"] {
+		t.Fatalf("unexpected deleted records %v", deleted)
+	}
+	if path := server.requests[1].URL.Path; path != "/services/data/v"+DefaultAPIVersion+"/queryAll" {
+		t.Fatalf("unexpected path %q", path)
+	}
+}
+
+func TestClient_ResumeQuery(t *testing.T) {
+	ctx := context.Background()
+
+	server := newTestQueryServer(t, 5, 2)
+	client := newLoggedInTestClient(t, server)
+
+	it := client.QueryIter(ctx, "SELECT Id, Name FROM User")
+	for i := 0; i < 3; i++ {
+		if !it.Next() {
+			t.Fatal(it.Err())
+		}
+	}
+	data, err := json.Marshal(it.Cursor())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Resume in another client, as if in another process.
+	var cursor QueryCursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		t.Fatal(err)
+	}
+	if cursor.Position != 3 || cursor.Done {
+		t.Fatalf("unexpected cursor %s", data)
+	}
+	resumed := newLoggedInTestClient(t, server).ResumeQuery(ctx, cursor)
+	var names []string
+	for resumed.Next() {
+		names = append(names, resumed.Record().StringField("Name"))
+	}
+	if err := resumed.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if len(names) != 2 || names[0] != "User 3" || names[1] != "User 4" {
+		t.Fatalf("unexpected records %v", names)
+	}
+	if path := server.requests[2].URL.Path; !strings.HasSuffix(path, "/query/"+testQueryLocator+"-3") {
+		t.Fatalf("unexpected path %q", path)
+	}
+
+	// A finished iteration resumes without any request.
+	cursor = resumed.Cursor()
+	if !cursor.Done || cursor.Position != 5 {
+		t.Fatalf("unexpected cursor %+v", cursor)
+	}
+	count := server.requestCount()
+	if client.ResumeQuery(ctx, cursor).Next() || server.requestCount() != count {
+		t.Fatal("expected no more records")
+	}
+
+	// Negative: expired locator.
+	cursor = QueryCursor{Resource: "query", Query: "SELECT Id, Name FROM User", Locator: cursor.Locator, Position: 99}
+	expired := client.ResumeQuery(ctx, cursor)
+	if expired.Next() || !errors.Is(expired.Err(), ErrQueryLocatorExpired) {
+		t.Fatalf("expected ErrQueryLocatorExpired, got %v", expired.Err())
+	}
+}
+
+func TestClient_ResumeQuerySingleBatch(t *testing.T) {
+	ctx := context.Background()
+
+	server := newTestQueryServer(t, 3, 10)
+	client := newLoggedInTestClient(t, server)
+
+	it := client.QueryAllIter(ctx, "SELECT Id, Name FROM User")
+	if !it.Next() {
+		t.Fatal(it.Err())
+	}
+	cursor := it.Cursor()
+	if cursor.Locator != "" || cursor.Resource != "queryAll" {
+		t.Fatalf("unexpected cursor %+v", cursor)
+	}
+
+	// Without a locator, the query runs again and skips the records consumed.
+	resumed := client.ResumeQuery(ctx, cursor)
+	var names []string
+	for resumed.Next() {
+		names = append(names, resumed.Record().StringField("Name"))
+	}
+	if err := resumed.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if len(names) != 2 || names[0] != "User 1" {
+		t.Fatalf("unexpected records %v", names)
+	}
+	if path := server.requests[1].URL.Path; path != "/services/data/v"+DefaultAPIVersion+"/queryAll" {
+		t.Fatalf("unexpected path %q", path)
+	}
+}
@@ -0,0 +1,141 @@
+package simpleforce
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// soslReservedCharacters are the characters which must be escaped in SOSL search terms.
+const soslReservedCharacters = `?&|!{}[]()^~*:\"'+-`
+
+// SearchResult holds the response data from an SOSL search.
+type SearchResult struct {
+	SearchRecords []SObject `json:"searchRecords"`
+}
+
+// SearchRequest holds the parameters of a parameterized search.
+// Ref: https://developer.salesforce.com/docs/atlas.en-us.api_rest.meta/api_rest/resources_search_parameterized.htm
+type SearchRequest struct {
+	// Q is the search string, escaped with EscapeSOSL where needed.
+	Q string `json:"q"`
+	// In is the scope of fields to search: ALL, NAME, EMAIL, PHONE or SIDEBAR. Defaults to ALL.
+	In string `json:"in,omitempty"`
+	// Fields are the fields returned for each object which doesn't list its own fields.
+	Fields []string `json:"fields,omitempty"`
+	// SObjects restricts the search to these objects. Defaults to all searchable objects.
+	SObjects []SearchSObject `json:"sobjects,omitempty"`
+	// OverallLimit is the maximum number of records returned in total.
+	OverallLimit int `json:"overallLimit,omitempty"`
+	// DefaultLimit is the maximum number of records returned per object which doesn't set its own limit.
+	DefaultLimit int `json:"defaultLimit,omitempty"`
+}
+
+// SearchSObject restricts a parameterized search to an object.
+type SearchSObject struct {
+	Name   string   `json:"name"`
+	Fields []string `json:"fields,omitempty"`
+	// Where is an SOQL condition filtering the records of the object.
+	Where string `json:"where,omitempty"`
+	Limit int    `json:"limit,omitempty"`
+}
+
+// Search runs an SOSL search, e.g. "FIND {jdoe@example.com} IN EMAIL FIELDS RETURNING Contact(Id), User(Id)".
+// Ref: https://developer.salesforce.com/docs/atlas.en-us.api_rest.meta/api_rest/resources_search.htm
+func (client *Client) Search(ctx context.Context, sosl string) (*SearchResult, error) {
+	if !client.isLoggedIn() {
+		return nil, ErrAuthentication
+	}
+
+	u := client.makeURL("search?q=" + url.QueryEscape(sosl))
+	data, err := client.httpRequest(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return client.parseSearchResult(data)
+}
+
+// ParameterizedSearch runs a search described by req, without writing SOSL.
+// Ref: https://developer.salesforce.com/docs/atlas.en-us.api_rest.meta/api_rest/resources_search_parameterized.htm
+func (client *Client) ParameterizedSearch(ctx context.Context, req *SearchRequest) (*SearchResult, error) {
+	if !client.isLoggedIn() {
+		return nil, ErrAuthentication
+	}
+
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+
+	u := client.makeURL("parameterizedSearch/")
+	data, err := client.httpRequest(ctx, http.MethodPost, u, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	return client.parseSearchResult(data)
+}
+
+// parseSearchResult decodes a search response and associates its records with the client.
+func (client *Client) parseSearchResult(data []byte) (*SearchResult, error) {
+	var result SearchResult
+	// Searches without matches may return an empty array instead of an object.
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		err := json.Unmarshal(trimmed, &result.SearchRecords)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse search result: %w", err)
+		}
+	} else {
+		err := json.Unmarshal(data, &result)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse search result: %w", err)
+		}
+	}
+
+	// Reference to client is needed if the object will be further used to do online queries.
+	for idx := range result.SearchRecords {
+		result.SearchRecords[idx].setClient(client)
+	}
+
+	return &result, nil
+}
+
+// ByType groups the records by their SObject type, keeping the order of the results within each type.
+func (result *SearchResult) ByType() map[string][]SObject {
+	byType := make(map[string][]SObject)
+	for _, record := range result.SearchRecords {
+		typeName := record.Type()
+		byType[typeName] = append(byType[typeName], record)
+	}
+	return byType
+}
+
+// Records returns the records of the SObject type typeName.
+func (result *SearchResult) Records(typeName string) []SObject {
+	var records []SObject
+	for _, record := range result.SearchRecords {
+		if record.Type() == typeName {
+			records = append(records, record)
+		}
+	}
+	return records
+}
+
+// EscapeSOSL escapes the reserved characters of SOSL in a search term, so that it matches literally, e.g.
+// "FIND {" + EscapeSOSL(email) + "} IN EMAIL FIELDS".
+// Ref: https://developer.salesforce.com/docs/atlas.en-us.soql_sosl.meta/soql_sosl/sforce_api_calls_sosl_find.htm
+func EscapeSOSL(term string) string {
+	var b strings.Builder
+	for _, r := range term {
+		if strings.ContainsRune(soslReservedCharacters, r) {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,100 @@
+package simpleforce
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+const testSearchResponse = `{"searchRecords": [
+	{"attributes": {"type": "Contact", "url": "/services/data/v54.0/sobjects/Contact/003000000000001AAA"}, "Id": "003000000000001AAA"},
+	{"attributes": {"type": "User", "url": "/services/data/v54.0/sobjects/User/005000000000001AAA"}, "Id": "005000000000001AAA"},
+	{"attributes": {"type": "Contact", "url": "/services/data/v54.0/sobjects/Contact/003000000000002AAA"}, "Id": "003000000000002AAA"}
+]}`
+
+func TestClient_Search(t *testing.T) {
+	ctx := context.Background()
+
+	const sosl = `FIND {jdoe\@example.com} IN EMAIL FIELDS RETURNING Contact(Id), User(Id)`
+	mux := http.NewServeMux()
+	mux.HandleFunc("/services/data/v"+DefaultAPIVersion+"/search", func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("q") {
+		case sosl:
+			_, _ = w.Write([]byte(testSearchResponse))
+		case "FIND {nothing}":
+			_, _ = w.Write([]byte(`[]`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`[{"message":"unexpected token","errorCode":"INVALID_SEARCH"}]`))
+		}
+	})
+	client := newLoggedInTestClient(t, mux)
+
+	result, err := client.Search(ctx, sosl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	byType := result.ByType()
+	if len(result.SearchRecords) != 3 || len(byType["Contact"]) != 2 || len(byType["User"]) != 1 {
+		t.Fatalf("unexpected result %+v", result)
+	}
+	contacts := result.Records("Contact")
+	if len(contacts) != 2 || contacts[1].ID() != "003000000000002AAA" || contacts[1].client() != client {
+		t.Fatalf("unexpected contacts %+v", contacts)
+	}
+
+	result, err = client.Search(ctx, "FIND {nothing}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.SearchRecords) != 0 {
+		t.Fatalf("unexpected result %+v", result)
+	}
+
+	// Negative: invalid search.
+	var sfErr SalesforceError
+	if _, err := client.Search(ctx, "FIND"); !errors.As(err, &sfErr) || sfErr.ErrorCode != "INVALID_SEARCH" {
+		t.Fatalf("expected INVALID_SEARCH, got %v", err)
+	}
+}
+
+func TestClient_ParameterizedSearch(t *testing.T) {
+	ctx := context.Background()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/services/data/v"+DefaultAPIVersion+"/parameterizedSearch/", func(w http.ResponseWriter, r *http.Request) {
+		var req SearchRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Error(err)
+		}
+		if r.Method != http.MethodPost || req.Q != "jdoe@example.com" || req.In != "EMAIL" ||
+			len(req.SObjects) != 2 || req.SObjects[1].Name != "User" || req.SObjects[1].Where != "IsActive = true" {
+			t.Errorf("unexpected request %s %+v", r.Method, req)
+		}
+		_, _ = w.Write([]byte(testSearchResponse))
+	})
+	client := newLoggedInTestClient(t, mux)
+
+	result, err := client.ParameterizedSearch(ctx, &SearchRequest{
+		Q:  "jdoe@example.com",
+		In: "EMAIL",
+		SObjects: []SearchSObject{
+			{Name: "Contact", Fields: []string{"Id"}},
+			{Name: "User", Fields: []string{"Id"}, Where: "IsActive = true"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Records("User")) != 1 {
+		t.Fatalf("unexpected result %+v", result)
+	}
+}
+
+func TestEscapeSOSL(t *testing.T) {
+	if actual := EscapeSOSL(`o'brien+1@example.com (test)`); actual != `o\'brien\+1@example.com \(test\)` {
+		t.Fatalf("unexpected escaped term %q", actual)
+	}
+}
@@ -0,0 +1,157 @@
+package simpleforce
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+const (
+	soapEnvelopeNamespace = "http://schemas.xmlsoap.org/soap/envelope/"
+	soapPartnerNamespace  = "urn:partner.soap.sforce.com"
+	xsdNamespace          = "http://www.w3.org/2001/XMLSchema"
+	xsiNamespace          = "http://www.w3.org/2001/XMLSchema-instance"
+)
+
+// soapEnvelope is the request envelope of a partner SOAP API call. All values are escaped by the XML encoder, so
+// requests can safely carry arbitrary user input.
+// Ref: https://developer.salesforce.com/docs/atlas.en-us.api.meta/api/sforce_api_quickstart_intro.htm
+type soapEnvelope struct {
+	XMLName xml.Name   `xml:"env:Envelope"`
+	XSD     string     `xml:"xmlns:xsd,attr"`
+	XSI     string     `xml:"xmlns:xsi,attr"`
+	Env     string     `xml:"xmlns:env,attr"`
+	URN     string     `xml:"xmlns:urn,attr"`
+	Header  soapHeader `xml:"env:Header"`
+	Body    soapBody   `xml:"env:Body"`
+}
+
+// soapHeader holds the SOAP headers sent with a call.
+type soapHeader struct {
+	CallOptions   *soapCallOptions   `xml:"urn:CallOptions,omitempty"`
+	SessionHeader *soapSessionHeader `xml:"urn:SessionHeader,omitempty"`
+}
+
+// soapCallOptions is the CallOptions header identifying the client.
+type soapCallOptions struct {
+	Client           string `xml:"urn:client"`
+	DefaultNamespace string `xml:"urn:defaultNamespace,omitempty"`
+}
+
+// soapSessionHeader is the SessionHeader authorizing calls other than login.
+type soapSessionHeader struct {
+	SessionID string `xml:"urn:sessionId"`
+}
+
+// soapBody wraps the call. Request must be a struct whose XMLName names the call, e.g. "urn:login".
+type soapBody struct {
+	Request interface{}
+}
+
+// soapLoginRequest is the body of the login call.
+// Ref: https://developer.salesforce.com/docs/atlas.en-us.api.meta/api/sforce_api_calls_login.htm
+type soapLoginRequest struct {
+	XMLName  xml.Name `xml:"urn:login"`
+	Username string   `xml:"urn:username"`
+	Password string   `xml:"urn:password"`
+}
+
+// soapLoginResponse holds the response data of the login call.
+type soapLoginResponse struct {
+	XMLName      xml.Name `xml:"Envelope"`
+	ServerURL    string   `xml:"Body>loginResponse>result>serverUrl"`
+	SessionID    string   `xml:"Body>loginResponse>result>sessionId"`
+	UserID       string   `xml:"Body>loginResponse>result>userId"`
+	UserEmail    string   `xml:"Body>loginResponse>result>userInfo>userEmail"`
+	UserFullName string   `xml:"Body>loginResponse>result>userInfo>userFullName"`
+	UserName     string   `xml:"Body>loginResponse>result>userInfo>userName"`
+}
+
+// soapLogoutRequest is the body of the logout call.
+// Ref: https://developer.salesforce.com/docs/atlas.en-us.api.meta/api/sforce_api_calls_logout.htm
+type soapLogoutRequest struct {
+	XMLName xml.Name `xml:"urn:logout"`
+}
+
+// newSOAPEnvelope wraps request into an envelope. sessionID is left out of the headers if empty, as for login.
+func (client *Client) newSOAPEnvelope(sessionID string, request interface{}) *soapEnvelope {
+	envelope := &soapEnvelope{
+		XSD: xsdNamespace,
+		XSI: xsiNamespace,
+		Env: soapEnvelopeNamespace,
+		URN: soapPartnerNamespace,
+		Header: soapHeader{
+			CallOptions: &soapCallOptions{
+				Client:           client.clientID,
+				DefaultNamespace: "sf",
+			},
+		},
+		Body: soapBody{Request: request},
+	}
+	if sessionID != "" {
+		envelope.Header.SessionHeader = &soapSessionHeader{SessionID: sessionID}
+	}
+	return envelope
+}
+
+// soapCall posts envelope to the partner SOAP endpoint on host and decodes the response into response. SOAP faults are
+// returned as SalesforceError.
+func (client *Client) soapCall(ctx context.Context, host, action string, envelope *soapEnvelope, response interface{}) error {
+	l := ctxzap.Extract(ctx)
+
+	reqData, err := xml.Marshal(envelope)
+	if err != nil {
+		l.Error("error occurred encoding soap request", zap.Error(err))
+		return err
+	}
+	reqData = append([]byte(xml.Header), reqData...)
+
+	requestUrl := fmt.Sprintf("%s/services/Soap/u/%s", host, client.apiVersion)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, requestUrl, bytes.NewReader(reqData))
+	if err != nil {
+		l.Error("error occurred creating request", zap.Error(err))
+		return err
+	}
+	req.Header.Add("Content-Type", "text/xml; charset=UTF-8")
+	req.Header.Add("SOAPAction", action)
+
+	l.Debug("Sending SOAP request",
+		zap.String("request_url", requestUrl),
+		zap.String("action", action))
+
+	resp, err := client.httpClient.Do(req)
+	// Handle non-API errors
+	if err != nil && resp == nil {
+		l.Error("error occurred submitting request", zap.Error(err))
+		return err
+	}
+	defer resp.Body.Close()
+
+	respData, err := io.ReadAll(resp.Body)
+	if err != nil {
+		l.Error("error occurred reading response data", zap.Error(err))
+		return err
+	}
+
+	// We have a response, but there's still possibly an error from the request, so we'll attempt to parse the fault.
+	if resp.StatusCode != http.StatusOK {
+		l.Error("request failed", zap.Int("status_code", resp.StatusCode), zap.ByteString("body", respData))
+		return ParseSalesforceError(resp.StatusCode, respData)
+	}
+
+	if response == nil {
+		return nil
+	}
+	err = xml.Unmarshal(respData, response)
+	if err != nil {
+		l.Error("error occurred parsing soap response", zap.Error(err))
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package simpleforce
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+)
+
+const testLoginFault = `<?xml version="1.0" encoding="UTF-8"?>
+<soapenv:Envelope xmlns:soapenv="http://schemas.xmlsoap.org/soap/envelope/"
+	xmlns:sf="urn:fault.partner.soap.sforce.com" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance">
+	<soapenv:Body>
+		<soapenv:Fault>
+			<faultcode>sf:%[1]s</faultcode>
+			<faultstring>%[1]s: %[2]s</faultstring>
+			<detail>
+				<sf:LoginFault xsi:type="sf:LoginFault">
+					<sf:exceptionCode>%[1]s</sf:exceptionCode>
+					<sf:exceptionMessage>%[2]s</sf:exceptionMessage>
+				</sf:LoginFault>
+			</detail>
+		</soapenv:Fault>
+	</soapenv:Body>
+</soapenv:Envelope>`
+
+const testLoginResponse = `<?xml version="1.0" encoding="UTF-8"?>
+<soapenv:Envelope xmlns:soapenv="http://schemas.xmlsoap.org/soap/envelope/" xmlns="urn:partner.soap.sforce.com">
+	<soapenv:Body>
+		<loginResponse>
+			<result>
+				<serverUrl>%s/services/Soap/u/54.0/00D000000000001</serverUrl>
+				<sessionId>__SESSION_ID__</sessionId>
+				<userId>005000000000001AAA</userId>
+				<userInfo>
+					<userEmail>user@example.com</userEmail>
+					<userFullName>Test User</userFullName>
+					<userName>%s</userName>
+				</userInfo>
+			</result>
+		</loginResponse>
+	</soapenv:Body>
+</soapenv:Envelope>`
+
+func TestClient_LoginPasswordSOAP(t *testing.T) {
+	ctx := context.Background()
+
+	// Credentials with XML special characters must arrive unchanged.
+	username := `a&b<c>"d'@example.com`
+	password := `p&ss</n1:password><x>`
+	token := `t<k>n&`
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/services/Soap/u/"+DefaultAPIVersion, func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		var login struct {
+			Client   string `xml:"Header>CallOptions>client"`
+			Username string `xml:"Body>login>username"`
+			Password string `xml:"Body>login>password"`
+		}
+		if err := xml.Unmarshal(body, &login); err != nil {
+			t.Errorf("invalid envelope: %v", err)
+		}
+		if r.Header.Get("SOAPAction") != "login" || login.Client != DefaultClientID {
+			t.Errorf("unexpected request %q for client %q", r.Header.Get("SOAPAction"), login.Client)
+		}
+
+		w.Header().Set("Content-Type", "text/xml")
+		switch {
+		case login.Username != username:
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = fmt.Fprintf(w, testLoginFault, "INVALID_LOGIN", "Invalid username, password, security token; or user locked out.")
+		case login.Password == password:
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = fmt.Fprintf(w, testLoginFault, "LOGIN_MUST_USE_SECURITY_TOKEN", "Invalid username, password, security token; or user locked out. Are you at a new location?")
+		case login.Password != password+token:
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = fmt.Fprintf(w, testLoginFault, "INVALID_LOGIN", "Invalid username, password, security token; or user locked out.")
+		default:
+			var escaped bytes.Buffer
+			_ = xml.EscapeText(&escaped, []byte(username))
+			_, _ = fmt.Fprintf(w, testLoginResponse, "http://"+r.Host, escaped.String())
+		}
+	})
+
+	client := newTestClient(t, mux)
+
+	err := client.LoginPassword(ctx, username, password, token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if client.GetSid() != "__SESSION_ID__" || client.GetLoc() != client.baseURL || client.user.name != username {
+		t.Fatalf("unexpected session %q at %q for %q", client.GetSid(), client.GetLoc(), client.user.name)
+	}
+
+	for _, tc := range []struct {
+		username, password, token, errorCode string
+	}{
+		{username, password, "", "LOGIN_MUST_USE_SECURITY_TOKEN"},
+		{username, "__WRONG__", token, "INVALID_LOGIN"},
+		{"someone@example.com", password, token, "INVALID_LOGIN"},
+	} {
+		err := client.LoginPassword(ctx, tc.username, tc.password, tc.token)
+		var sfErr SalesforceError
+		if !errors.As(err, &sfErr) || sfErr.ErrorCode != tc.errorCode || sfErr.HttpCode != http.StatusInternalServerError {
+			t.Errorf("expected %s, got %v", tc.errorCode, err)
+		}
+	}
+}
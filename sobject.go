@@ -7,6 +7,8 @@ import (
 	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
 	"go.uber.org/zap"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
 
 	"github.com/pkg/errors"
@@ -16,6 +18,7 @@ const (
 	sobjectClientKey              = "__client__" // private attribute added to locate client instance.
 	sobjectAttributesKey          = "attributes" // points to the attributes structure which should be common to all SObjects.
 	sobjectIDKey                  = "Id"
+	sobjectIsDeletedKey           = "IsDeleted"
 	sobjectExternalIDFieldNameKey = "ExternalIDField"
 )
 
@@ -55,15 +58,15 @@ type SObjectAttributes struct {
 	URL  string `json:"url"`
 }
 
-// Describe queries the metadata of an SObject using the "describe" API.
+// Describe queries the metadata of an SObject using the "describe" API. nil is returned on failure; use
+// DescribeSObject for a typed result and the error.
 // Ref: https://developer.salesforce.com/docs/atlas.en-us.214.0.api_rest.meta/api_rest/resources_sobject_describe.htm
 func (obj *SObject) Describe(ctx context.Context) *SObjectMeta {
 	if obj.Type() == "" || obj.client() == nil {
 		// Sanity check.
 		return nil
 	}
-	url := obj.client().makeURL("sobjects/" + obj.Type() + "/describe")
-	data, err := obj.client().httpRequest(ctx, http.MethodGet, url, nil)
+	data, err := obj.client().describe(ctx, obj.Type())
 	if err != nil {
 		return nil
 	}
@@ -81,25 +84,64 @@ func (obj *SObject) Describe(ctx context.Context) *SObjectMeta {
 // and id is not provided as the parameter, nil is returned.
 // If query is successful, the SObject is updated in-place and exact same address is returned; otherwise, nil is
 // returned if failed.
+// Use GetFields to retrieve only some fields, and GetByExternalID to retrieve an SObject by an external ID.
 func (obj *SObject) Get(ctx context.Context, id ...string) (*SObject, error) {
-	l := ctxzap.Extract(ctx)
+	oid := obj.ID()
+	if len(id) > 0 {
+		oid = id[0]
+	}
+	return obj.getByID(ctx, oid, nil)
+}
 
-	if obj.Type() == "" || obj.client() == nil {
+// GetFields retrieves the given fields of an SObject, or all of them if none are given. If id is empty, the existing
+// ID of the SObject is used; if there is none, nil is returned. Otherwise it behaves like Get.
+// Ref: https://developer.salesforce.com/docs/atlas.en-us.api_rest.meta/api_rest/resources_sobject_retrieve_get.htm
+func (obj *SObject) GetFields(ctx context.Context, id string, fields ...string) (*SObject, error) {
+	if id == "" {
+		id = obj.ID()
+	}
+	return obj.getByID(ctx, id, fields)
+}
+
+// GetByExternalID retrieves the SObject whose external ID field has the given value, e.g. a User by its
+// FederationIdentifier. Only the given fields are retrieved if any are given, otherwise all of them.
+// If query is successful, the SObject is updated in-place and exact same address is returned. A SalesforceError with
+// the code NOT_FOUND is returned if there is no such SObject.
+// Ref: https://developer.salesforce.com/docs/atlas.en-us.api_rest.meta/api_rest/dome_upsert.htm
+func (obj *SObject) GetByExternalID(ctx context.Context, field, value string, fields ...string) (*SObject, error) {
+	if obj.Type() == "" || obj.client() == nil || field == "" || value == "" {
 		// Sanity check.
 		return nil, ErrNoTypeIdClientOrId
 	}
 
-	oid := obj.ID()
-	if len(id) > 0 {
-		oid = id[0]
+	return obj.get(ctx, "sobjects/"+obj.Type()+"/"+url.PathEscape(field)+"/"+url.PathEscape(value), fields)
+}
+
+// getByID retrieves the SObject with the ID oid, limited to fields if any, into obj.
+func (obj *SObject) getByID(ctx context.Context, oid string, fields []string) (*SObject, error) {
+	l := ctxzap.Extract(ctx)
+
+	if obj.Type() == "" || obj.client() == nil {
+		// Sanity check.
+		return nil, ErrNoTypeIdClientOrId
 	}
 	if oid == "" {
 		l.Warn("oid not found")
 		return nil, nil
 	}
 
-	url := obj.client().makeURL("sobjects/" + obj.Type() + "/" + oid)
-	data, err := obj.client().httpRequest(ctx, http.MethodGet, url, nil)
+	return obj.get(ctx, "sobjects/"+obj.Type()+"/"+url.PathEscape(oid), fields)
+}
+
+// get retrieves the SObject at path, relative to the REST API, limited to fields if any, into obj.
+func (obj *SObject) get(ctx context.Context, path string, fields []string) (*SObject, error) {
+	l := ctxzap.Extract(ctx)
+
+	if len(fields) > 0 {
+		path += "?fields=" + url.QueryEscape(strings.Join(fields, ","))
+	}
+	u := obj.client().makeURL(path)
+	data, err := obj.client().httpRequest(ctx, http.MethodGet, u, nil)
 	if err != nil {
 		l.Warn("failed to process http request", zap.Error(err))
 		return nil, err
@@ -116,9 +158,9 @@ func (obj *SObject) Get(ctx context.Context, id ...string) (*SObject, error) {
 
 // Create posts the JSON representation of the SObject to salesforce to create the entry.
 // If the creation is successful, the ID of the SObject instance is updated with the ID returned. Otherwise, nil is
-// returned for failures.
+// returned for failures. opts customize the call, e.g. WithAutoAssign.
 // Ref: https://developer.salesforce.com/docs/atlas.en-us.214.0.api_rest.meta/api_rest/dome_sobject_create.htm
-func (obj *SObject) Create(ctx context.Context) (*SObject, error) {
+func (obj *SObject) Create(ctx context.Context, opts ...CallOption) (*SObject, error) {
 	l := ctxzap.Extract(ctx)
 
 	if obj.Type() == "" || obj.client() == nil {
@@ -135,7 +177,7 @@ func (obj *SObject) Create(ctx context.Context) (*SObject, error) {
 	}
 
 	url := obj.client().makeURL("sobjects/" + obj.Type() + "/")
-	respData, err := obj.client().httpRequest(ctx, http.MethodPost, url, bytes.NewReader(reqData))
+	respData, err := obj.client().httpRequest(ctx, http.MethodPost, url, bytes.NewReader(reqData), opts...)
 	if err != nil {
 		l.Warn("failed to process http request", zap.Error(err))
 		return nil, err
@@ -151,8 +193,8 @@ func (obj *SObject) Create(ctx context.Context) (*SObject, error) {
 }
 
 // Update updates SObject in place. Upon successful, same SObject is returned for chained access.
-// ID is required.
-func (obj *SObject) Update(ctx context.Context) (*SObject, error) {
+// ID is required. opts customize the call, e.g. WithAutoAssign.
+func (obj *SObject) Update(ctx context.Context, opts ...CallOption) (*SObject, error) {
 	l := ctxzap.Extract(ctx)
 
 	if obj.Type() == "" || obj.client() == nil || obj.ID() == "" {
@@ -173,7 +215,7 @@ func (obj *SObject) Update(ctx context.Context) (*SObject, error) {
 		queryBase = "tooling/sobjects/"
 	}
 	url := obj.client().makeURL(queryBase + obj.Type() + "/" + obj.ID())
-	respData, err := obj.client().httpRequest(ctx, http.MethodPatch, url, bytes.NewReader(reqData))
+	respData, err := obj.client().httpRequest(ctx, http.MethodPatch, url, bytes.NewReader(reqData), opts...)
 	if err != nil {
 		l.Warn("failed to process http request", zap.Error(err))
 		return nil, err
@@ -185,7 +227,8 @@ func (obj *SObject) Update(ctx context.Context) (*SObject, error) {
 
 // Upsert creates SObject or updates existing SObject in place. Upon successful upsert, same SObject is returned for chained access.
 // ID, ExternalIDField and Type are required. ID is the value of the external ID in this case.
-func (obj *SObject) Upsert(ctx context.Context) (*SObject, error) {
+// opts customize the call, e.g. WithAutoAssign.
+func (obj *SObject) Upsert(ctx context.Context, opts ...CallOption) (*SObject, error) {
 	l := ctxzap.Extract(ctx)
 
 	l.Info("Upserting SObject")
@@ -214,7 +257,7 @@ func (obj *SObject) Upsert(ctx context.Context) (*SObject, error) {
 	}
 	url := obj.client().
 		makeURL(queryBase + obj.Type() + "/" + obj.ExternalIDFieldName() + "/" + obj.ExternalID())
-	respData, err := obj.client().httpRequest(ctx, http.MethodPatch, url, bytes.NewReader(reqData))
+	respData, err := obj.client().httpRequest(ctx, http.MethodPatch, url, bytes.NewReader(reqData), opts...)
 	if err != nil {
 		l.Warn("failed to process http request", zap.Error(err))
 		return nil, err
@@ -298,6 +341,27 @@ func (obj *SObject) StringField(key string) string {
 	}
 }
 
+// BoolField accesses a field in the SObject as bool. Values of other types than bool are parsed if they're strings,
+// e.g. "true", and false is returned if the field doesn't exist or can't be parsed.
+func (obj *SObject) BoolField(key string) bool {
+	value := obj.InterfaceField(key)
+	switch value := value.(type) {
+	case bool:
+		return value
+	case string:
+		parsed, _ := strconv.ParseBool(value)
+		return parsed
+	default:
+		return false
+	}
+}
+
+// IsDeleted returns if the SObject has been deleted, as returned by QueryAll. The IsDeleted field must be selected by
+// the query, otherwise false is returned.
+func (obj *SObject) IsDeleted() bool {
+	return obj.BoolField(sobjectIsDeletedKey)
+}
+
 // SObjectField accesses a field in the SObject as another SObject. This is only applicable if the field is an external
 // ID to another object. The typeName of the SObject must be provided. <nil> is returned if the field is empty.
 func (obj *SObject) SObjectField(ctx context.Context, typeName, key string) *SObject {
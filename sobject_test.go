@@ -7,6 +7,7 @@ import (
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"log"
+	"net/http"
 	"testing"
 	"time"
 
@@ -56,6 +57,20 @@ func TestSObject_InterfaceField(t *testing.T) {
 	}
 }
 
+func TestSObject_BoolField(t *testing.T) {
+	obj := &SObject{
+		"IsActive":  true,
+		"IsDeleted": "true",
+		"Name":      "not a bool",
+	}
+	if !obj.BoolField("IsActive") || !obj.IsDeleted() {
+		t.Fail()
+	}
+	if obj.BoolField("Name") || obj.BoolField("Missing") {
+		t.Fail()
+	}
+}
+
 func TestSObject_SObjectField(t *testing.T) {
 	ctx := context.Background()
 
@@ -155,6 +170,68 @@ func TestSObject_Get(t *testing.T) {
 	}
 }
 
+func TestSObject_GetFields(t *testing.T) {
+	ctx := context.Background()
+
+	prefix := "/services/data/v" + DefaultAPIVersion + "/sobjects/User/"
+	mux := http.NewServeMux()
+	mux.HandleFunc(prefix, func(w http.ResponseWriter, r *http.Request) {
+		var id string
+		switch r.URL.EscapedPath() {
+		case prefix + "005000000000001AAA":
+			id = "005000000000001AAA"
+		case prefix + "FederationIdentifier/jane%2Fdoe@example.com":
+			id = "005000000000002AAA"
+		case prefix + "Odd%2FField/x":
+			id = "005000000000003AAA"
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`[{"message":"The requested resource does not exist","errorCode":"NOT_FOUND"}]`))
+			return
+		}
+		if fields := r.URL.Query().Get("fields"); fields != "Id,Email" {
+			t.Errorf("unexpected fields %q", fields)
+		}
+		_, _ = w.Write([]byte(`{"attributes": {"type": "User"}, "Id": "` + id + `", "Email": "jane@example.com"}`))
+	})
+	client := newLoggedInTestClient(t, mux)
+
+	obj, err := client.SObject("User").GetFields(ctx, "005000000000001AAA", "Id", "Email")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obj.ID() != "005000000000001AAA" || obj.StringField("Email") != "jane@example.com" {
+		t.Fatalf("unexpected sobject %v", obj)
+	}
+
+	obj, err = client.SObject("User").GetByExternalID(ctx, "FederationIdentifier", "jane/doe@example.com", "Id", "Email")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obj.ID() != "005000000000002AAA" || obj.Type() != "User" {
+		t.Fatalf("unexpected sobject %v", obj)
+	}
+
+	// The field name is escaped like the value.
+	obj, err = client.SObject("User").GetByExternalID(ctx, "Odd/Field", "x", "Id", "Email")
+	if err != nil || obj.ID() != "005000000000003AAA" {
+		t.Fatalf("unexpected sobject %v, %v", obj, err)
+	}
+
+	// Negative: no match, and missing parameters.
+	var sfErr SalesforceError
+	_, err = client.SObject("User").GetByExternalID(ctx, "FederationIdentifier", "nobody", "Id", "Email")
+	if !errors.As(err, &sfErr) || sfErr.ErrorCode != "NOT_FOUND" {
+		t.Fatalf("expected NOT_FOUND, got %v", err)
+	}
+	if _, err := client.SObject("User").GetByExternalID(ctx, "FederationIdentifier", ""); !errors.Is(err, ErrNoTypeIdClientOrId) {
+		t.Fatalf("expected ErrNoTypeIdClientOrId, got %v", err)
+	}
+	if obj, err := client.SObject("User").GetFields(ctx, "", "Id"); obj != nil || err != nil {
+		t.Fatalf("expected nothing, got %v, %v", obj, err)
+	}
+}
+
 func TestSObject_Create(t *testing.T) {
 	ctx := context.Background()
 
@@ -0,0 +1,179 @@
+package soql
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Condition is an expression of a WHERE clause. Conditions are created with the functions of this package, which
+// escape their values.
+type Condition struct {
+	expr string
+}
+
+// String renders the condition as SOQL.
+func (c Condition) String() string {
+	return c.expr
+}
+
+// Raw returns a condition rendered verbatim. It is never escaped, so it must not contain user input.
+func Raw(expr string) Condition {
+	return Condition{expr: expr}
+}
+
+func compare(field, operator string, value interface{}) Condition {
+	return Condition{expr: field + " " + operator + " " + Format(value)}
+}
+
+// Eq matches records where field equals value.
+func Eq(field string, value interface{}) Condition {
+	return compare(field, "=", value)
+}
+
+// Ne matches records where field doesn't equal value.
+func Ne(field string, value interface{}) Condition {
+	return compare(field, "!=", value)
+}
+
+// Lt matches records where field is less than value.
+func Lt(field string, value interface{}) Condition {
+	return compare(field, "<", value)
+}
+
+// Le matches records where field is less than or equal to value.
+func Le(field string, value interface{}) Condition {
+	return compare(field, "<=", value)
+}
+
+// Gt matches records where field is greater than value.
+func Gt(field string, value interface{}) Condition {
+	return compare(field, ">", value)
+}
+
+// Ge matches records where field is greater than or equal to value.
+func Ge(field string, value interface{}) Condition {
+	return compare(field, ">=", value)
+}
+
+// IsNull matches records where field is null.
+func IsNull(field string) Condition {
+	return compare(field, "=", nil)
+}
+
+// IsNotNull matches records where field isn't null.
+func IsNotNull(field string) Condition {
+	return compare(field, "!=", nil)
+}
+
+// Like matches records where field matches pattern, in which % and _ are wildcards and \% and \_ match them literally.
+// The pattern is escaped as a string literal, so it must not be escaped with Escape. Use EscapeLike to match user
+// input literally.
+func Like(field, pattern string) Condition {
+	return Condition{expr: field + " LIKE '" + escapeLikePattern(pattern) + "'"}
+}
+
+// In matches records where field equals one of values.
+func In(field string, values ...interface{}) Condition {
+	return Condition{expr: field + " IN " + formatList(values)}
+}
+
+// NotIn matches records where field equals none of values.
+func NotIn(field string, values ...interface{}) Condition {
+	return Condition{expr: field + " NOT IN " + formatList(values)}
+}
+
+// InStrings is In for a list of strings.
+func InStrings(field string, values ...string) Condition {
+	return In(field, stringValues(values)...)
+}
+
+// NotInStrings is NotIn for a list of strings.
+func NotInStrings(field string, values ...string) Condition {
+	return NotIn(field, stringValues(values)...)
+}
+
+// InQuery matches records where field is one of the values selected by the semi-join subquery sub.
+func InQuery(field string, sub *Query) Condition {
+	return Condition{expr: field + " IN (" + sub.String() + ")"}
+}
+
+// NotInQuery matches records where field is none of the values selected by the anti-join subquery sub.
+func NotInQuery(field string, sub *Query) Condition {
+	return Condition{expr: field + " NOT IN (" + sub.String() + ")"}
+}
+
+// Includes matches records where the multi-select picklist field includes any of values. A value may combine several
+// picklist values separated by ; which must all be included.
+func Includes(field string, values ...string) Condition {
+	return Condition{expr: field + " INCLUDES " + formatList(stringValues(values))}
+}
+
+// Excludes matches records where the multi-select picklist field includes none of values.
+func Excludes(field string, values ...string) Condition {
+	return Condition{expr: field + " EXCLUDES " + formatList(stringValues(values))}
+}
+
+// And matches records matching all conditions.
+func And(conditions ...Condition) Condition {
+	return group(conditions, " AND ")
+}
+
+// Or matches records matching any of conditions.
+func Or(conditions ...Condition) Condition {
+	return group(conditions, " OR ")
+}
+
+// Not matches records not matching condition.
+func Not(condition Condition) Condition {
+	return Condition{expr: "(NOT " + condition.expr + ")"}
+}
+
+func group(conditions []Condition, operator string) Condition {
+	if len(conditions) == 1 {
+		return conditions[0]
+	}
+	return Condition{expr: "(" + joinConditions(conditions, operator) + ")"}
+}
+
+func joinConditions(conditions []Condition, operator string) string {
+	exprs := make([]string, len(conditions))
+	for i, c := range conditions {
+		exprs[i] = c.expr
+	}
+	return strings.Join(exprs, operator)
+}
+
+func formatList(values []interface{}) string {
+	formatted := make([]string, len(values))
+	for i, v := range values {
+		formatted[i] = Format(v)
+	}
+	return "(" + strings.Join(formatted, ", ") + ")"
+}
+
+func stringValues(values []string) []interface{} {
+	result := make([]interface{}, len(values))
+	for i, v := range values {
+		result[i] = v
+	}
+	return result
+}
+
+// escapeLikePattern escapes a LIKE pattern like Escape, keeping the escape sequences \% and \_ intact.
+func escapeLikePattern(pattern string) string {
+	var b strings.Builder
+	for i := 0; i < len(pattern); i++ {
+		if pattern[i] == '\\' && i+1 < len(pattern) && (pattern[i+1] == '%' || pattern[i+1] == '_') {
+			b.WriteString(pattern[i : i+2])
+			i++
+			continue
+		}
+		if pattern[i] >= utf8.RuneSelf {
+			// Only ASCII characters are escaped, so multi-byte characters are copied as is.
+			b.WriteByte(pattern[i])
+			continue
+		}
+		b.WriteString(Escape(pattern[i : i+1]))
+	}
+	return b.String()
+}
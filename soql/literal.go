@@ -0,0 +1,158 @@
+package soql
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Layouts of the date and datetime literals of SOQL.
+const (
+	dateLayout     = "2006-01-02"
+	dateTimeLayout = "2006-01-02T15:04:05Z"
+)
+
+// Escape escapes s for use inside a quoted SOQL string literal.
+// Ref: https://developer.salesforce.com/docs/atlas.en-us.soql_sosl.meta/soql_sosl/sforce_api_calls_soql_select_quotedstringescapes.htm
+func Escape(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch r {
+		case '\\':
+			b.WriteString(`\\`)
+		case '\'':
+			b.WriteString(`\'`)
+		case '"':
+			b.WriteString(`\"`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		case '\b':
+			b.WriteString(`\b`)
+		case '\f':
+			b.WriteString(`\f`)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// EscapeLike escapes the LIKE wildcards % and _ in s, so that s matches literally when used in the pattern of Like, e.g.
+// Like("Name", "%"+EscapeLike(name)+"%"). Like escapes the rest of the pattern, so the result must not be escaped
+// again with Escape.
+func EscapeLike(s string) string {
+	s = strings.ReplaceAll(s, "%", `\%`)
+	return strings.ReplaceAll(s, "_", `\_`)
+}
+
+// Quote returns s as a quoted and escaped SOQL string literal.
+func Quote(s string) string {
+	return "'" + Escape(s) + "'"
+}
+
+// Literal is a value rendered verbatim in SOQL, such as a date literal. It is never escaped, so it must not contain
+// user input.
+type Literal string
+
+// Date literals, relative to the current day.
+// Ref: https://developer.salesforce.com/docs/atlas.en-us.soql_sosl.meta/soql_sosl/sforce_api_calls_soql_select_dateformats.htm
+const (
+	Yesterday    Literal = "YESTERDAY"
+	Today        Literal = "TODAY"
+	Tomorrow     Literal = "TOMORROW"
+	LastWeek     Literal = "LAST_WEEK"
+	ThisWeek     Literal = "THIS_WEEK"
+	NextWeek     Literal = "NEXT_WEEK"
+	LastMonth    Literal = "LAST_MONTH"
+	ThisMonth    Literal = "THIS_MONTH"
+	NextMonth    Literal = "NEXT_MONTH"
+	Last90Days   Literal = "LAST_90_DAYS"
+	Next90Days   Literal = "NEXT_90_DAYS"
+	LastQuarter  Literal = "LAST_QUARTER"
+	ThisQuarter  Literal = "THIS_QUARTER"
+	NextQuarter  Literal = "NEXT_QUARTER"
+	LastYear     Literal = "LAST_YEAR"
+	ThisYear     Literal = "THIS_YEAR"
+	NextYear     Literal = "NEXT_YEAR"
+	NullLiteral  Literal = "null"
+	TrueLiteral  Literal = "true"
+	FalseLiteral Literal = "false"
+)
+
+// LastNDays returns the date literal LAST_N_DAYS:n.
+func LastNDays(n int) Literal {
+	return Literal("LAST_N_DAYS:" + strconv.Itoa(n))
+}
+
+// NextNDays returns the date literal NEXT_N_DAYS:n.
+func NextNDays(n int) Literal {
+	return Literal("NEXT_N_DAYS:" + strconv.Itoa(n))
+}
+
+// LastNMonths returns the date literal LAST_N_MONTHS:n.
+func LastNMonths(n int) Literal {
+	return Literal("LAST_N_MONTHS:" + strconv.Itoa(n))
+}
+
+// NextNMonths returns the date literal NEXT_N_MONTHS:n.
+func NextNMonths(n int) Literal {
+	return Literal("NEXT_N_MONTHS:" + strconv.Itoa(n))
+}
+
+// Date returns the date of t as a date literal, for comparisons with date fields. Datetime fields are compared with a
+// time.Time value instead.
+func Date(t time.Time) Literal {
+	return Literal(t.Format(dateLayout))
+}
+
+// Format renders v as a SOQL literal: strings are quoted and escaped, booleans and numbers are rendered as is,
+// time.Time values as UTC datetimes, nil as null and Literal values verbatim. Values of other types are rendered as
+// quoted strings.
+func Format(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return string(NullLiteral)
+	case Literal:
+		return string(v)
+	case string:
+		return Quote(v)
+	case time.Time:
+		return v.UTC().Format(dateTimeLayout)
+	case *time.Time:
+		if v == nil {
+			return string(NullLiteral)
+		}
+		return v.UTC().Format(dateTimeLayout)
+	case fmt.Stringer:
+		return Quote(v.String())
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.String:
+		return Quote(rv.String())
+	case reflect.Bool:
+		return strconv.FormatBool(rv.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(rv.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(rv.Uint(), 10)
+	case reflect.Float32:
+		return strconv.FormatFloat(rv.Float(), 'f', -1, 32)
+	case reflect.Float64:
+		return strconv.FormatFloat(rv.Float(), 'f', -1, 64)
+	case reflect.Pointer:
+		if rv.IsNil() {
+			return string(NullLiteral)
+		}
+		return Format(rv.Elem().Interface())
+	default:
+		return Quote(fmt.Sprint(v))
+	}
+}
@@ -0,0 +1,172 @@
+// Package soql builds SOQL queries, escaping all values so that user input can't alter the query.
+//
+//	q := soql.Select("Id", "Name").
+//		From("User").
+//		Where(soql.Eq("Email", email), soql.Gt("CreatedDate", soql.LastNDays(30))).
+//		OrderBy("Name").
+//		Limit(10)
+//	result, err := client.Query(ctx, q.String())
+//
+// Field and object names are rendered verbatim and must not contain user input; values are rendered with Format.
+// Ref: https://developer.salesforce.com/docs/atlas.en-us.soql_sosl.meta/soql_sosl/sforce_api_calls_soql_select.htm
+package soql
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Query is a SOQL SELECT statement. Its methods modify and return the query, so that they can be chained.
+type Query struct {
+	fields     []string
+	subqueries []*Query
+	object     string
+	where      []Condition
+	groupBy    string
+	having     []Condition
+	orderBy    []string
+	limit      int
+	offset     int
+}
+
+// Select starts a query selecting fields.
+func Select(fields ...string) *Query {
+	return &Query{fields: fields}
+}
+
+// Select adds fields to the selected fields.
+func (q *Query) Select(fields ...string) *Query {
+	q.fields = append(q.fields, fields...)
+	return q
+}
+
+// SelectSubquery adds a child relationship subquery to the selected fields, e.g.
+// Select("Id").SelectSubquery(Select("Id").From("Contacts")).From("Account").
+func (q *Query) SelectSubquery(sub *Query) *Query {
+	q.subqueries = append(q.subqueries, sub)
+	return q
+}
+
+// From sets the object queried, or the child relationship for a subquery.
+func (q *Query) From(object string) *Query {
+	q.object = object
+	return q
+}
+
+// Where adds conditions to the WHERE clause. All conditions, including those of previous calls, must match.
+func (q *Query) Where(conditions ...Condition) *Query {
+	q.where = append(q.where, conditions...)
+	return q
+}
+
+// GroupBy groups the records by fields, for aggregate functions such as COUNT(Id) or SUM(Amount) in the selected
+// fields.
+// Ref: https://developer.salesforce.com/docs/atlas.en-us.soql_sosl.meta/soql_sosl/sforce_api_calls_soql_select_groupby.htm
+func (q *Query) GroupBy(fields ...string) *Query {
+	q.groupBy = strings.Join(fields, ", ")
+	return q
+}
+
+// GroupByRollup groups the records by fields like GroupBy, adding subtotal rows for each level of grouping.
+func (q *Query) GroupByRollup(fields ...string) *Query {
+	q.groupBy = "ROLLUP(" + strings.Join(fields, ", ") + ")"
+	return q
+}
+
+// GroupByCube groups the records by fields like GroupBy, adding subtotal rows for all combinations of grouped fields.
+func (q *Query) GroupByCube(fields ...string) *Query {
+	q.groupBy = "CUBE(" + strings.Join(fields, ", ") + ")"
+	return q
+}
+
+// Having adds conditions on the grouped records to the HAVING clause, e.g. Gt("COUNT(Id)", 10). All conditions,
+// including those of previous calls, must match.
+func (q *Query) Having(conditions ...Condition) *Query {
+	q.having = append(q.having, conditions...)
+	return q
+}
+
+// OrderBy adds fields to the ORDER BY clause, in ascending order.
+func (q *Query) OrderBy(fields ...string) *Query {
+	q.orderBy = append(q.orderBy, fields...)
+	return q
+}
+
+// OrderByDesc adds fields to the ORDER BY clause, in descending order.
+func (q *Query) OrderByDesc(fields ...string) *Query {
+	for _, field := range fields {
+		q.orderBy = append(q.orderBy, field+" DESC")
+	}
+	return q
+}
+
+// Limit sets the maximum number of records returned. Zero means no limit.
+func (q *Query) Limit(n int) *Query {
+	q.limit = n
+	return q
+}
+
+// Offset sets the number of records skipped. Zero means none.
+func (q *Query) Offset(n int) *Query {
+	q.offset = n
+	return q
+}
+
+// Clone returns a copy of the query, which can be modified without affecting q.
+func (q *Query) Clone() *Query {
+	clone := *q
+	clone.fields = append([]string(nil), q.fields...)
+	clone.subqueries = make([]*Query, len(q.subqueries))
+	for i, sub := range q.subqueries {
+		clone.subqueries[i] = sub.Clone()
+	}
+	clone.where = append([]Condition(nil), q.where...)
+	clone.having = append([]Condition(nil), q.having...)
+	clone.orderBy = append([]string(nil), q.orderBy...)
+	return &clone
+}
+
+// String renders the query as SOQL.
+func (q *Query) String() string {
+	var b strings.Builder
+
+	fields := make([]string, 0, len(q.fields)+len(q.subqueries))
+	fields = append(fields, q.fields...)
+	for _, sub := range q.subqueries {
+		fields = append(fields, "("+sub.String()+")")
+	}
+	if len(fields) == 0 {
+		fields = append(fields, "Id")
+	}
+
+	b.WriteString("SELECT ")
+	b.WriteString(strings.Join(fields, ", "))
+	b.WriteString(" FROM ")
+	b.WriteString(q.object)
+
+	if len(q.where) > 0 {
+		b.WriteString(" WHERE ")
+		b.WriteString(joinConditions(q.where, " AND "))
+	}
+	if q.groupBy != "" {
+		b.WriteString(" GROUP BY ")
+		b.WriteString(q.groupBy)
+	}
+	if len(q.having) > 0 {
+		b.WriteString(" HAVING ")
+		b.WriteString(joinConditions(q.having, " AND "))
+	}
+	if len(q.orderBy) > 0 {
+		b.WriteString(" ORDER BY ")
+		b.WriteString(strings.Join(q.orderBy, ", "))
+	}
+	if q.limit > 0 {
+		b.WriteString(" LIMIT ")
+		b.WriteString(strconv.Itoa(q.limit))
+	}
+	if q.offset > 0 {
+		b.WriteString(" OFFSET ")
+		b.WriteString(strconv.Itoa(q.offset))
+	}
+	return b.String()
+}
@@ -0,0 +1,141 @@
+package soql
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEscape(t *testing.T) {
+	for input, expected := range map[string]string{
+		"O'Brien":          `O\'Brien`,
+		`back\slash`:       `back\\slash`,
+		"line\nbreak\ttab": `line\nbreak\ttab`,
+		`"quoted"`:         `\"quoted\"`,
+		"Zoë":              "Zoë",
+	} {
+		if actual := Escape(input); actual != expected {
+			t.Errorf("Escape(%q) = %q, expected %q", input, actual, expected)
+		}
+	}
+
+	if actual := EscapeLike("50%_off's"); actual != `50\%\_off's` {
+		t.Errorf("unexpected EscapeLike %q", actual)
+	}
+}
+
+func TestFormat(t *testing.T) {
+	type status string
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.FixedZone("CET", 3600))
+	for _, tc := range []struct {
+		value    interface{}
+		expected string
+	}{
+		{"it's", `'it\'s'`},
+		{status("Active"), `'Active'`},
+		{true, "true"},
+		{42, "42"},
+		{uint8(7), "7"},
+		{1.5, "1.5"},
+		{nil, "null"},
+		{(*int)(nil), "null"},
+		{created, "2024-01-02T02:04:05Z"},
+		{Date(created), "2024-01-02"},
+		{LastNDays(30), "LAST_N_DAYS:30"},
+	} {
+		if actual := Format(tc.value); actual != tc.expected {
+			t.Errorf("Format(%#v) = %q, expected %q", tc.value, actual, tc.expected)
+		}
+	}
+}
+
+func TestQuery(t *testing.T) {
+	q := Select("Id", "Name").
+		SelectSubquery(Select("Id", "Email").From("Contacts").Where(Eq("IsDeleted", false)).Limit(5)).
+		From("Account").
+		Where(
+			Eq("Name", "Acme' OR Name != '"),
+			Or(Gt("CreatedDate", LastNDays(30)), IsNull("LastActivityDate")),
+		).
+		Where(Not(InStrings("Type", "Partner", "Competitor"))).
+		OrderBy("Name").
+		OrderByDesc("CreatedDate").
+		Limit(10).
+		Offset(20)
+
+	expected := "SELECT Id, Name, (SELECT Id, Email FROM Contacts WHERE IsDeleted = false LIMIT 5) FROM Account" +
+		` WHERE Name = 'Acme\' OR Name != \'' AND (CreatedDate > LAST_N_DAYS:30 OR LastActivityDate = null)` +
+		" AND (NOT Type IN ('Partner', 'Competitor'))" +
+		" ORDER BY Name, CreatedDate DESC LIMIT 10 OFFSET 20"
+	if actual := q.String(); actual != expected {
+		t.Fatalf("unexpected query\n%s\nexpected\n%s", actual, expected)
+	}
+}
+
+func TestQueryClone(t *testing.T) {
+	q := Select("Id").SelectSubquery(Select("Id").From("Contacts")).From("Account").Where(Eq("Type", "Partner"))
+	clone := q.Clone().Select("Name").Where(IsNotNull("Name")).OrderBy("Name")
+	clone.subqueries[0].Where(Eq("IsDeleted", false))
+
+	if actual := q.String(); actual != "SELECT Id, (SELECT Id FROM Contacts) FROM Account WHERE Type = 'Partner'" {
+		t.Fatalf("unexpected original query %s", actual)
+	}
+	expected := "SELECT Id, Name, (SELECT Id FROM Contacts WHERE IsDeleted = false) FROM Account" +
+		" WHERE Type = 'Partner' AND Name != null ORDER BY Name"
+	if actual := clone.String(); actual != expected {
+		t.Fatalf("unexpected cloned query %s", actual)
+	}
+}
+
+func TestQueryGroupBy(t *testing.T) {
+	q := Select("Department", "COUNT(Id) total").
+		From("User").
+		Where(Eq("IsActive", true)).
+		GroupByRollup("Department").
+		Having(Gt("COUNT(Id)", 10)).
+		OrderByDesc("COUNT(Id)")
+
+	expected := "SELECT Department, COUNT(Id) total FROM User WHERE IsActive = true" +
+		" GROUP BY ROLLUP(Department) HAVING COUNT(Id) > 10 ORDER BY COUNT(Id) DESC"
+	if actual := q.String(); actual != expected {
+		t.Fatalf("unexpected query\n%s\nexpected\n%s", actual, expected)
+	}
+
+	q = Select("Type", "COUNT(Id)").From("Account").GroupBy("Type")
+	if actual := q.String(); actual != "SELECT Type, COUNT(Id) FROM Account GROUP BY Type" {
+		t.Fatalf("unexpected query %s", actual)
+	}
+}
+
+func TestConditions(t *testing.T) {
+	for _, tc := range []struct {
+		condition Condition
+		expected  string
+	}{
+		{Ne("Name", "x"), "Name != 'x'"},
+		{Le("Amount", 10), "Amount <= 10"},
+		{Ge("CloseDate", Today), "CloseDate >= TODAY"},
+		{Lt("CreatedDate", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)), "CreatedDate < 2024-01-01T00:00:00Z"},
+		{IsNotNull("Email"), "Email != null"},
+		{Like("Name", "%"+EscapeLike("100%")+"%"), `Name LIKE '%100\%%'`},
+		{Like("Name", "O'Br%"), `Name LIKE 'O\'Br%'`},
+		{Like("Name", "%"+EscapeLike("O'Brien")+"%"), `Name LIKE '%O\'Brien%'`},
+		{Like("Name", "%"+EscapeLike(`a\b`)+"%"), `Name LIKE '%a\\b%'`},
+		{Like("Name", EscapeLike(`a\%_`)), `Name LIKE 'a\\\%\_'`},
+		{In("Amount", 1, 2.5), "Amount IN (1, 2.5)"},
+		{NotInStrings("Id", "a", "b"), "Id NOT IN ('a', 'b')"},
+		{Includes("Regions__c", "EMEA;APAC", "AMER"), "Regions__c INCLUDES ('EMEA;APAC', 'AMER')"},
+		{Excludes("Regions__c", "AMER"), "Regions__c EXCLUDES ('AMER')"},
+		{And(Eq("A", 1)), "A = 1"},
+		{And(Eq("A", 1), Or(Eq("B", 2), Eq("C", 3))), "(A = 1 AND (B = 2 OR C = 3))"},
+		{
+			InQuery("Id", Select("AssigneeId").From("PermissionSetAssignment").Where(Eq("PermissionSet.Name", "Admin"))),
+			"Id IN (SELECT AssigneeId FROM PermissionSetAssignment WHERE PermissionSet.Name = 'Admin')",
+		},
+		{NotInQuery("Id", Select("ContactId").From("Case")), "Id NOT IN (SELECT ContactId FROM Case)"},
+		{Raw("DISTANCE(Location__c, GEOLOCATION(1, 2), 'km') < 10"), "DISTANCE(Location__c, GEOLOCATION(1, 2), 'km') < 10"},
+	} {
+		if actual := tc.condition.String(); actual != tc.expected {
+			t.Errorf("unexpected condition %q, expected %q", actual, tc.expected)
+		}
+	}
+}
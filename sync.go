@@ -0,0 +1,155 @@
+package simpleforce
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// MaxSyncWindow is the longest window GetUpdated and GetDeleted accept. Use SyncWindows to split longer windows.
+const MaxSyncWindow = 30 * 24 * time.Hour
+
+// syncDateTimeLayout is the layout of the start and end parameters of GetUpdated and GetDeleted.
+const syncDateTimeLayout = "2006-01-02T15:04:05-07:00"
+
+// UpdatedResult holds the IDs of the records updated in a window, as returned by GetUpdated.
+type UpdatedResult struct {
+	IDs []string
+	// LatestDateCovered is the time up to which the result is complete, which may be before the end of the window.
+	// Use it as the start of the next window.
+	LatestDateCovered time.Time
+}
+
+// DeletedResult holds the records deleted in a window, as returned by GetDeleted.
+type DeletedResult struct {
+	DeletedRecords []DeletedRecord
+	// EarliestDateAvailable is the time of the oldest deletion still available. Deletions before it are lost, e.g.
+	// because the recycle bin was emptied.
+	EarliestDateAvailable time.Time
+	// LatestDateCovered is the time up to which the result is complete, which may be before the end of the window.
+	// Use it as the start of the next window.
+	LatestDateCovered time.Time
+}
+
+// DeletedRecord is a record deleted in the window of GetDeleted.
+type DeletedRecord struct {
+	ID          string
+	DeletedDate time.Time
+}
+
+// SyncWindow is a window of time for GetUpdated and GetDeleted.
+type SyncWindow struct {
+	Start time.Time
+	End   time.Time
+}
+
+// GetUpdated returns the IDs of the records of type typeName which were created or updated between start and end.
+// Salesforce only keeps track of the last 30 days, and the window can't be longer than MaxSyncWindow.
+// Ref: https://developer.salesforce.com/docs/atlas.en-us.api_rest.meta/api_rest/resources_getupdated.htm
+func (client *Client) GetUpdated(ctx context.Context, typeName string, start, end time.Time) (*UpdatedResult, error) {
+	var response struct {
+		IDs               []string `json:"ids"`
+		LatestDateCovered string   `json:"latestDateCovered"`
+	}
+	err := client.getSyncWindow(ctx, typeName, "updated", start, end, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &UpdatedResult{IDs: response.IDs}
+	result.LatestDateCovered, err = parseOptionalTime(response.LatestDateCovered)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GetDeleted returns the records of type typeName which were deleted between start and end. Salesforce only keeps
+// track of the last 30 days, and the window can't be longer than MaxSyncWindow.
+// Ref: https://developer.salesforce.com/docs/atlas.en-us.api_rest.meta/api_rest/resources_getdeleted.htm
+func (client *Client) GetDeleted(ctx context.Context, typeName string, start, end time.Time) (*DeletedResult, error) {
+	var response struct {
+		DeletedRecords []struct {
+			ID          string `json:"id"`
+			DeletedDate string `json:"deletedDate"`
+		} `json:"deletedRecords"`
+		EarliestDateAvailable string `json:"earliestDateAvailable"`
+		LatestDateCovered     string `json:"latestDateCovered"`
+	}
+	err := client.getSyncWindow(ctx, typeName, "deleted", start, end, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &DeletedResult{}
+	for _, record := range response.DeletedRecords {
+		deletedDate, err := parseOptionalTime(record.DeletedDate)
+		if err != nil {
+			return nil, err
+		}
+		result.DeletedRecords = append(result.DeletedRecords, DeletedRecord{ID: record.ID, DeletedDate: deletedDate})
+	}
+	result.EarliestDateAvailable, err = parseOptionalTime(response.EarliestDateAvailable)
+	if err != nil {
+		return nil, err
+	}
+	result.LatestDateCovered, err = parseOptionalTime(response.LatestDateCovered)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// SyncWindows splits the window from start to end into consecutive windows no longer than MaxSyncWindow, for
+// GetUpdated and GetDeleted.
+func SyncWindows(start, end time.Time) []SyncWindow {
+	var windows []SyncWindow
+	for start.Before(end) {
+		windowEnd := start.Add(MaxSyncWindow)
+		if windowEnd.After(end) {
+			windowEnd = end
+		}
+		windows = append(windows, SyncWindow{Start: start, End: windowEnd})
+		start = windowEnd
+	}
+	return windows
+}
+
+// getSyncWindow requests the updated or deleted records of typeName between start and end, decoding the response
+// into result.
+func (client *Client) getSyncWindow(ctx context.Context, typeName, resource string, start, end time.Time,
+	result interface{}) error {
+	if !client.isLoggedIn() {
+		return ErrAuthentication
+	}
+	if typeName == "" {
+		return ErrNoTypeIdClientOrId
+	}
+
+	params := url.Values{}
+	params.Set("start", start.UTC().Format(syncDateTimeLayout))
+	params.Set("end", end.UTC().Format(syncDateTimeLayout))
+	u := client.makeURL("sobjects/" + typeName + "/" + resource + "/?" + params.Encode())
+
+	data, err := client.httpRequest(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+
+	err = json.Unmarshal(data, result)
+	if err != nil {
+		return fmt.Errorf("failed to parse %s records: %w", resource, err)
+	}
+	return nil
+}
+
+// parseOptionalTime parses a datetime returned by salesforce, returning the zero time for an empty value.
+func parseOptionalTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	return parseSalesforceTime(value)
+}
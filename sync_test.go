@@ -0,0 +1,91 @@
+package simpleforce
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestClient_GetUpdatedDeleted(t *testing.T) {
+	ctx := context.Background()
+
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 3, 2, 1, 0, 0, 0, time.FixedZone("CET", 3600))
+
+	prefix := "/services/data/v" + DefaultAPIVersion + "/sobjects/User/"
+	mux := http.NewServeMux()
+	checkWindow := func(r *http.Request) bool {
+		query := r.URL.Query()
+		if query.Get("start") != "2024-03-01T00:00:00+00:00" || query.Get("end") != "2024-03-02T00:00:00+00:00" {
+			t.Errorf("unexpected window %s", r.URL.RawQuery)
+			return false
+		}
+		return true
+	}
+	mux.HandleFunc(prefix+"updated/", func(w http.ResponseWriter, r *http.Request) {
+		if checkWindow(r) {
+			_, _ = w.Write([]byte(`{"ids": ["005000000000001AAA", "005000000000002AAA"], "latestDateCovered": "2024-03-01T23:45:00.000+0000"}`))
+		}
+	})
+	mux.HandleFunc(prefix+"deleted/", func(w http.ResponseWriter, r *http.Request) {
+		if checkWindow(r) {
+			_, _ = w.Write([]byte(`{
+				"deletedRecords": [{"id": "005000000000003AAA", "deletedDate": "2024-03-01T12:00:00.000+0000"}],
+				"earliestDateAvailable": "2024-02-01T00:00:00.000+0000",
+				"latestDateCovered": "2024-03-01T23:45:00.000+0000"
+			}`))
+		}
+	})
+	mux.HandleFunc("/services/data/v"+DefaultAPIVersion+"/sobjects/Unknown/updated/", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`[{"message":"The requested resource does not exist","errorCode":"NOT_FOUND"}]`))
+	})
+	client := newLoggedInTestClient(t, mux)
+
+	latest := time.Date(2024, 3, 1, 23, 45, 0, 0, time.UTC)
+	updated, err := client.GetUpdated(ctx, "User", start, end)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(updated.IDs) != 2 || updated.IDs[1] != "005000000000002AAA" || !updated.LatestDateCovered.Equal(latest) {
+		t.Fatalf("unexpected result %+v", updated)
+	}
+
+	deleted, err := client.GetDeleted(ctx, "User", start, end)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deleted.DeletedRecords) != 1 || deleted.DeletedRecords[0].ID != "005000000000003AAA" ||
+		!deleted.DeletedRecords[0].DeletedDate.Equal(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)) ||
+		!deleted.EarliestDateAvailable.Equal(time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)) ||
+		!deleted.LatestDateCovered.Equal(latest) {
+		t.Fatalf("unexpected result %+v", deleted)
+	}
+
+	// Negative: unknown object.
+	var sfErr SalesforceError
+	if _, err := client.GetUpdated(ctx, "Unknown", start, end); !errors.As(err, &sfErr) || sfErr.ErrorCode != "NOT_FOUND" {
+		t.Fatalf("expected NOT_FOUND, got %v", err)
+	}
+	if _, err := client.GetDeleted(ctx, "", start, end); !errors.Is(err, ErrNoTypeIdClientOrId) {
+		t.Fatalf("expected ErrNoTypeIdClientOrId, got %v", err)
+	}
+}
+
+func TestSyncWindows(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	windows := SyncWindows(start, start.Add(70*24*time.Hour))
+	if len(windows) != 3 {
+		t.Fatalf("expected 3 windows, got %+v", windows)
+	}
+	if !windows[0].Start.Equal(start) || !windows[1].Start.Equal(windows[0].End) ||
+		windows[1].End.Sub(windows[1].Start) != MaxSyncWindow || windows[2].End.Sub(windows[2].Start) != 10*24*time.Hour {
+		t.Fatalf("unexpected windows %+v", windows)
+	}
+
+	if windows := SyncWindows(start, start); len(windows) != 0 {
+		t.Fatalf("expected no windows, got %+v", windows)
+	}
+}
@@ -0,0 +1,238 @@
+package simpleforce
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// Token is a client session as persisted by a TokenStore.
+type Token struct {
+	AccessToken  string    `json:"access_token"`
+	RefreshToken string    `json:"refresh_token,omitempty"`
+	InstanceURL  string    `json:"instance_url"`
+	IdentityURL  string    `json:"identity_url,omitempty"`
+	IssuedAt     time.Time `json:"issued_at"`
+}
+
+// TokenStore persists client sessions, so that processes working against the same org can share a session instead of
+// each logging in.
+type TokenStore interface {
+	// Load returns the token stored under key, or nil if there is none.
+	Load(ctx context.Context, key string) (*Token, error)
+	// Save stores token under key, replacing any existing token.
+	Save(ctx context.Context, key string, token *Token) error
+	// Delete removes the token stored under key. Deleting a missing token is not an error.
+	Delete(ctx context.Context, key string) error
+}
+
+// UseTokenStore makes the client share its session through store under key, which should identify the org and user.
+// A session found in store is adopted right away and true is returned, in which case LoginPassword doesn't need to be
+// called; OAuth logins keep the adopted session and only use their grant once it expires. Every login and renewal
+// afterwards is saved to store.
+func (client *Client) UseTokenStore(ctx context.Context, store TokenStore, key string) (bool, error) {
+	client.tokenStore = store
+	client.tokenStoreKey = key
+
+	tok, err := store.Load(ctx, key)
+	if err != nil {
+		return false, err
+	}
+	if tok == nil || tok.AccessToken == "" {
+		return false, nil
+	}
+
+	client.adoptToken(tok)
+	client.sessionMu.Lock()
+	client.restoredSession = true
+	client.sessionMu.Unlock()
+	return true, nil
+}
+
+// adoptToken replaces the current session with a stored token.
+func (client *Client) adoptToken(tok *Token) {
+	client.sessionMu.Lock()
+	defer client.sessionMu.Unlock()
+	client.sessionID = tok.AccessToken
+	client.instanceURL = tok.InstanceURL
+	client.identityURL = tok.IdentityURL
+	client.issuedAt = tok.IssuedAt
+	if tok.RefreshToken != "" {
+		client.refreshToken = tok.RefreshToken
+	}
+}
+
+// loadStoredToken returns the token in the token store, or nil if there is no token store or it holds no token.
+func (client *Client) loadStoredToken(ctx context.Context) *Token {
+	if client.tokenStore == nil {
+		return nil
+	}
+
+	tok, err := client.tokenStore.Load(ctx, client.tokenStoreKey)
+	if err != nil {
+		ctxzap.Extract(ctx).Warn("failed to load token", zap.Error(err))
+		return nil
+	}
+	return tok
+}
+
+// saveToken writes the current session to the token store, if any. Failures are logged but not returned as the
+// session itself is still usable.
+func (client *Client) saveToken(ctx context.Context) {
+	if client.tokenStore == nil {
+		return
+	}
+
+	client.sessionMu.RLock()
+	tok := &Token{
+		AccessToken:  client.sessionID,
+		RefreshToken: client.refreshToken,
+		InstanceURL:  client.instanceURL,
+		IdentityURL:  client.identityURL,
+		IssuedAt:     client.issuedAt,
+	}
+	client.sessionMu.RUnlock()
+
+	err := client.tokenStore.Save(ctx, client.tokenStoreKey, tok)
+	if err != nil {
+		ctxzap.Extract(ctx).Warn("failed to save token", zap.Error(err))
+	}
+}
+
+// MemoryTokenStore is a TokenStore keeping tokens in memory, to share a session between clients of one process.
+type MemoryTokenStore struct {
+	mu     sync.Mutex
+	tokens map[string]Token
+}
+
+// NewMemoryTokenStore creates an empty MemoryTokenStore.
+func NewMemoryTokenStore() *MemoryTokenStore {
+	return &MemoryTokenStore{tokens: make(map[string]Token)}
+}
+
+// Load returns the token stored under key, or nil if there is none.
+func (store *MemoryTokenStore) Load(_ context.Context, key string) (*Token, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	tok, ok := store.tokens[key]
+	if !ok {
+		return nil, nil
+	}
+	return &tok, nil
+}
+
+// Save stores token under key.
+func (store *MemoryTokenStore) Save(_ context.Context, key string, token *Token) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	store.tokens[key] = *token
+	return nil
+}
+
+// Delete removes the token stored under key.
+func (store *MemoryTokenStore) Delete(_ context.Context, key string) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	delete(store.tokens, key)
+	return nil
+}
+
+// FileTokenStore is a TokenStore keeping tokens in a directory, one file per key, encrypted with AES-GCM. It allows
+// processes on the same host to share a session.
+type FileTokenStore struct {
+	dir  string
+	aead cipher.AEAD
+}
+
+// NewFileTokenStore creates a FileTokenStore writing to dir, which is created if needed. encryptionKey must be 16, 24
+// or 32 bytes long to select AES-128, AES-192 or AES-256.
+func NewFileTokenStore(dir string, encryptionKey []byte) (*FileTokenStore, error) {
+	block, err := aes.NewCipher(encryptionKey)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	err = os.MkdirAll(dir, 0o700)
+	if err != nil {
+		return nil, err
+	}
+
+	return &FileTokenStore{dir: dir, aead: aead}, nil
+}
+
+// Load returns the token stored under key, or nil if there is none.
+func (store *FileTokenStore) Load(_ context.Context, key string) (*Token, error) {
+	data, err := os.ReadFile(store.path(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	nonceSize := store.aead.NonceSize()
+	if len(data) < nonceSize {
+		return nil, errors.New("token file is corrupted")
+	}
+	plaintext, err := store.aead.Open(nil, data[:nonceSize], data[nonceSize:], []byte(key))
+	if err != nil {
+		return nil, err
+	}
+
+	var tok Token
+	err = json.Unmarshal(plaintext, &tok)
+	if err != nil {
+		return nil, err
+	}
+	return &tok, nil
+}
+
+// Save stores token under key, replacing the file atomically.
+func (store *FileTokenStore) Save(_ context.Context, key string, token *Token) error {
+	plaintext, err := json.Marshal(token)
+	if err != nil {
+		return err
+	}
+
+	nonce := make([]byte, store.aead.NonceSize())
+	_, err = io.ReadFull(rand.Reader, nonce)
+	if err != nil {
+		return err
+	}
+	data := store.aead.Seal(nonce, nonce, plaintext, []byte(key))
+
+	return writeFileAtomic(store.dir, hashedFileName(key, ".token"), data)
+}
+
+// Delete removes the token stored under key.
+func (store *FileTokenStore) Delete(_ context.Context, key string) error {
+	err := os.Remove(store.path(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// path returns the file holding the token of key.
+func (store *FileTokenStore) path(key string) string {
+	return filepath.Join(store.dir, hashedFileName(key, ".token"))
+}
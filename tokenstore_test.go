@@ -0,0 +1,162 @@
+package simpleforce
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestFileTokenStore(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	encryptionKey := bytes.Repeat([]byte{0x42}, 32)
+
+	store, err := NewFileTokenStore(dir, encryptionKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tok, err := store.Load(ctx, "org")
+	if err != nil || tok != nil {
+		t.Fatalf("expected no token, got %v, %v", tok, err)
+	}
+
+	saved := &Token{
+		AccessToken:  "__ACCESS_TOKEN__",
+		RefreshToken: "__REFRESH_TOKEN__",
+		InstanceURL:  "https://example.my.salesforce.com",
+		IssuedAt:     time.UnixMilli(1700000000000).UTC(),
+	}
+	if err := store.Save(ctx, "org", saved); err != nil {
+		t.Fatal(err)
+	}
+
+	tok, err = store.Load(ctx, "org")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *tok != *saved {
+		t.Fatalf("loaded %+v, saved %+v", tok, saved)
+	}
+
+	// Tokens must not be readable from disk.
+	files, err := filepath.Glob(filepath.Join(dir, "*"))
+	if err != nil || len(files) != 1 {
+		t.Fatalf("expected a single token file, got %v, %v", files, err)
+	}
+	data, err := os.ReadFile(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(data, []byte(saved.AccessToken)) {
+		t.Fatal("token file is not encrypted")
+	}
+
+	// Negative: another encryption key can't read the token.
+	otherStore, err := NewFileTokenStore(dir, bytes.Repeat([]byte{0x24}, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := otherStore.Load(ctx, "org"); err == nil {
+		t.Fatal("expected error")
+	}
+
+	if err := store.Delete(ctx, "org"); err != nil {
+		t.Fatal(err)
+	}
+	if tok, err := store.Load(ctx, "org"); err != nil || tok != nil {
+		t.Fatalf("expected no token, got %v, %v", tok, err)
+	}
+	if err := store.Delete(ctx, "org"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Negative: invalid encryption key.
+	if _, err := NewFileTokenStore(dir, []byte("short")); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestClient_UseTokenStore(t *testing.T) {
+	ctx := context.Background()
+
+	var (
+		mu         sync.Mutex
+		issued     int
+		validToken string
+	)
+	mux := newTestOAuthServer(t, func(r *http.Request) string {
+		mu.Lock()
+		defer mu.Unlock()
+		issued++
+		validToken = fmt.Sprintf("__ACCESS_TOKEN_%d__", issued)
+		return validToken
+	})
+	mux.HandleFunc("/services/data/v"+DefaultAPIVersion+"/query", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		valid := r.Header.Get("Authorization") == "Bearer "+validToken
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		if !valid {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`[{"message":"Session expired or invalid","errorCode":"INVALID_SESSION_ID"}]`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"totalSize":0,"done":true,"records":[]}`))
+	})
+
+	store := NewMemoryTokenStore()
+	client1 := newTestClient(t, mux)
+	client2, err := NewClient(ctx, client1.baseURL, DefaultClientID, DefaultAPIVersion)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The first client finds nothing stored and logs in.
+	restored, err := client1.UseTokenStore(ctx, store, "org")
+	if err != nil || restored {
+		t.Fatalf("expected nothing to restore, got %v, %v", restored, err)
+	}
+	if err := client1.LoginClientCredentials(ctx, "__CONSUMER_KEY__", "__CONSUMER_SECRET__"); err != nil {
+		t.Fatal(err)
+	}
+
+	// The second client reuses the session of the first one.
+	restored, err = client2.UseTokenStore(ctx, store, "org")
+	if err != nil || !restored {
+		t.Fatalf("expected a restored session, got %v, %v", restored, err)
+	}
+	if err := client2.LoginClientCredentials(ctx, "__CONSUMER_KEY__", "__CONSUMER_SECRET__"); err != nil {
+		t.Fatal(err)
+	}
+	if issued != 1 || client2.GetSid() != "__ACCESS_TOKEN_1__" || client2.user.id != testUserID {
+		t.Fatalf("expected the stored session to be reused, got %d logins and session %q", issued, client2.GetSid())
+	}
+
+	// Once the session expires, the first client renews it and the second one picks up the renewed session.
+	mu.Lock()
+	validToken = "__EXPIRED__"
+	mu.Unlock()
+
+	if _, err := client1.Query(ctx, "SELECT Id FROM User"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client2.Query(ctx, "SELECT Id FROM User"); err != nil {
+		t.Fatal(err)
+	}
+	if issued != 2 || client2.GetSid() != "__ACCESS_TOKEN_2__" {
+		t.Fatalf("expected a single renewal, got %d logins and session %q", issued, client2.GetSid())
+	}
+
+	tok, err := store.Load(ctx, "org")
+	if err != nil || tok.AccessToken != "__ACCESS_TOKEN_2__" || tok.InstanceURL != client1.baseURL {
+		t.Fatalf("unexpected stored token %+v, %v", tok, err)
+	}
+}
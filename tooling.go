@@ -41,7 +41,7 @@ func (client *Client) ExecuteAnonymous(ctx context.Context, apexBody string) (*E
 
 	// Create the endpoint
 	formatString := "%s/services/data/v%s/tooling/executeAnonymous/?anonymousBody=%s"
-	baseURL := client.instanceURL
+	baseURL := client.getInstanceURL()
 	endpoint := fmt.Sprintf(formatString, baseURL, client.apiVersion, url.QueryEscape(apexBody))
 
 	data, err := client.httpRequest(ctx, http.MethodGet, endpoint, nil)